@@ -40,7 +40,18 @@ func (a *algo) initDifficulty(r Rating, clamp bool) float64 {
 // nextInterval computes the next review interval in days.
 // I(r, S) = round((S / FACTOR) * (r^(1/DECAY) - 1)), clamped to [1, maxIvl].
 func (a *algo) nextInterval(stability, desiredRetention float64, maxIvl int) int {
-	ivl := stability / a.factor * (math.Pow(desiredRetention, 1.0/a.decay) - 1)
+	return a.intervalWithTerm(stability, a.retentionTerm(desiredRetention), maxIvl)
+}
+
+// retentionTerm returns r^(1/DECAY) - 1, the part of I(r, S) that depends
+// only on the desired retention r, for callers that precompute it.
+func (a *algo) retentionTerm(desiredRetention float64) float64 {
+	return math.Pow(desiredRetention, 1.0/a.decay) - 1
+}
+
+// intervalWithTerm is nextInterval with a precomputed retentionTerm.
+func (a *algo) intervalWithTerm(stability, term float64, maxIvl int) int {
+	ivl := stability / a.factor * term
 	rounded := int(math.Round(ivl))
 	if rounded < 1 {
 		rounded = 1
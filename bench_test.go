@@ -63,3 +63,23 @@ func BenchmarkPreviewCard(b *testing.B) {
 		s.PreviewCard(card, now)
 	}
 }
+
+// BenchmarkReviewCardParallel measures fuzzed reviews on one shared Scheduler
+// from many goroutines.
+func BenchmarkReviewCardParallel(b *testing.B) {
+	s, err := flux.NewScheduler(flux.SchedulerConfig{Seed: 1})
+	if err != nil {
+		b.Fatal(err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	card := flux.NewCard(1)
+	card, _ = s.ReviewCard(card, flux.Easy, now)
+	now = now.Add(10 * 24 * time.Hour)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.ReviewCard(card, flux.Good, now)
+		}
+	})
+}
@@ -1,28 +1,83 @@
 package flux
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // Card represents a flashcard with its scheduling state.
 type Card struct {
-	CardID     int64      `json:"card_id"`
-	State      State      `json:"state"`
-	Step       *int       `json:"step"`       // nil when State=Review.
-	Stability  *float64   `json:"stability"`  // nil before first review.
-	Difficulty *float64   `json:"difficulty"` // nil before first review.
-	Due        time.Time  `json:"due"`
-	LastReview *time.Time `json:"last_review"` // nil before first review.
+	CardID      int64      `json:"card_id"`
+	Key         string     `json:"key,omitempty"` // Optional string identity, such as a UUID; paired with CardID.
+	State       State      `json:"state"`
+	Step        *int       `json:"step"`       // nil when State=Review.
+	Stability   *float64   `json:"stability"`  // nil before first review.
+	Difficulty  *float64   `json:"difficulty"` // nil before first review.
+	Due         time.Time  `json:"due"`
+	LastReview  *time.Time `json:"last_review"`  // nil before first review.
+	Reps        int        `json:"reps"`         // Reviews so far.
+	Lapses      int        `json:"lapses"`       // Again ratings while in Review state.
+	FirstReview *time.Time `json:"first_review"` // nil before first review.
+	Leech       bool       `json:"leech"`        // Set once the card reaches the leech threshold.
+	Queue       Queue      `json:"queue"`        // QueueActive unless suspended or buried.
+	BuriedUntil *time.Time `json:"buried_until"` // nil unless Queue=QueueBuried.
 }
 
 // NewCard creates a new card in the Learning state with the given ID.
 // Due is set to now (immediately reviewable).
+// Use NewCardAt or Scheduler.NewCard to control the time.
 func NewCard(id int64) Card {
+	return NewCardAt(id, time.Now())
+}
+
+// NewCardAt creates a new card in the Learning state with the given ID,
+// due at now.
+func NewCardAt(id int64, now time.Time) Card {
 	step := 0
 	return Card{
 		CardID: id,
 		State:  Learning,
 		Step:   &step,
-		Due:    time.Now(),
+		Due:    now,
+	}
+}
+
+// matches returns an error wrapping ErrCardIDMismatch unless log records
+// this card: its CardID and CardKey must equal the card's CardID and Key.
+func (c Card) matches(log ReviewLog) error {
+	switch {
+	case log.CardID != c.CardID:
+		return fmt.Errorf("%w: card %d, log %d", ErrCardIDMismatch, c.CardID, log.CardID)
+	case log.CardKey != c.Key:
+		return fmt.Errorf("%w: card %q, log %q", ErrCardIDMismatch, c.Key, log.CardKey)
 	}
+	return nil
+}
+
+// Validate reports whether the card is in a state ReviewCard can process.
+// It checks that State and Queue are valid, that Stability and Difficulty
+// are either both nil or both set, with Stability positive and Difficulty
+// in [1, 10], and that Step, Reps and Lapses are not negative.
+// Returns an error wrapping ErrInvalidCard describing the first problem.
+func (c Card) Validate() error {
+	switch {
+	case !c.State.isValid():
+		return fmt.Errorf("%w: card %d has state %v", ErrInvalidCard, c.CardID, c.State)
+	case !c.Queue.isValid():
+		return fmt.Errorf("%w: card %d has queue %v", ErrInvalidCard, c.CardID, c.Queue)
+	case (c.Stability == nil) != (c.Difficulty == nil):
+		return fmt.Errorf("%w: card %d has only one of stability and difficulty", ErrInvalidCard, c.CardID)
+	case c.Stability != nil && !(*c.Stability > 0 && !math.IsInf(*c.Stability, 1)):
+		return fmt.Errorf("%w: card %d has stability %v", ErrInvalidCard, c.CardID, *c.Stability)
+	case c.Difficulty != nil && !(*c.Difficulty >= 1 && *c.Difficulty <= 10):
+		return fmt.Errorf("%w: card %d has difficulty %v", ErrInvalidCard, c.CardID, *c.Difficulty)
+	case c.Step != nil && *c.Step < 0:
+		return fmt.Errorf("%w: card %d has step %d", ErrInvalidCard, c.CardID, *c.Step)
+	case c.Reps < 0 || c.Lapses < 0:
+		return fmt.Errorf("%w: card %d has reps %d, lapses %d", ErrInvalidCard, c.CardID, c.Reps, c.Lapses)
+	}
+	return nil
 }
 
 // clone returns a deep copy of the card. Pointer fields are copied by value.
@@ -44,6 +99,14 @@ func (c Card) clone() Card {
 		v := *c.LastReview
 		out.LastReview = &v
 	}
+	if c.FirstReview != nil {
+		v := *c.FirstReview
+		out.FirstReview = &v
+	}
+	if c.BuriedUntil != nil {
+		v := *c.BuriedUntil
+		out.BuriedUntil = &v
+	}
 	return out
 }
 
@@ -2,6 +2,8 @@ package flux
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 	"time"
 )
@@ -42,6 +44,8 @@ func TestCardClone(t *testing.T) {
 	c.Difficulty = &d
 	c.Step = &step
 	c.LastReview = &now
+	first := now.Add(-time.Hour)
+	c.FirstReview = &first
 
 	cloned := c.clone()
 
@@ -75,6 +79,10 @@ func TestCardClone(t *testing.T) {
 	if *c.Step == 99 {
 		t.Error("clone Step pointer not independent")
 	}
+	*cloned.FirstReview = now
+	if c.FirstReview.Equal(now) {
+		t.Error("clone FirstReview pointer not independent")
+	}
 }
 
 func TestCardCloneNilFields(t *testing.T) {
@@ -128,15 +136,20 @@ func TestCardJSONRoundTrip(t *testing.T) {
 	s := 3.5
 	d := 5.0
 	step := 1
+	first := now.Add(-30 * 24 * time.Hour)
 
 	c := Card{
-		CardID:     42,
-		State:      Review,
-		Step:       &step,
-		Stability:  &s,
-		Difficulty: &d,
-		Due:        now,
-		LastReview: &now,
+		CardID:      42,
+		Key:         "card-42",
+		State:       Review,
+		Step:        &step,
+		Stability:   &s,
+		Difficulty:  &d,
+		Due:         now,
+		LastReview:  &now,
+		Reps:        7,
+		Lapses:      2,
+		FirstReview: &first,
 	}
 
 	data, err := json.Marshal(c)
@@ -149,6 +162,9 @@ func TestCardJSONRoundTrip(t *testing.T) {
 		t.Fatalf("Unmarshal: %v", err)
 	}
 
+	if got.Key != c.Key {
+		t.Errorf("Key = %q, want %q", got.Key, c.Key)
+	}
 	if got.CardID != c.CardID {
 		t.Errorf("CardID = %d, want %d", got.CardID, c.CardID)
 	}
@@ -170,6 +186,28 @@ func TestCardJSONRoundTrip(t *testing.T) {
 	if !got.LastReview.Equal(*c.LastReview) {
 		t.Errorf("LastReview = %v, want %v", got.LastReview, c.LastReview)
 	}
+	if got.Reps != c.Reps || got.Lapses != c.Lapses {
+		t.Errorf("Reps, Lapses = %d, %d, want %d, %d", got.Reps, got.Lapses, c.Reps, c.Lapses)
+	}
+	if !got.FirstReview.Equal(*c.FirstReview) {
+		t.Errorf("FirstReview = %v, want %v", got.FirstReview, c.FirstReview)
+	}
+}
+
+func TestCardJSONWithoutCounts(t *testing.T) {
+	// JSON written before Reps, Lapses and FirstReview existed.
+	data := `{"card_id":1,"state":"Review","step":null,"stability":5,"difficulty":5,` +
+		`"due":"2025-06-20T10:00:00Z","last_review":"2025-06-15T10:00:00Z"}`
+	var got Card
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Reps != 0 || got.Lapses != 0 || got.FirstReview != nil {
+		t.Errorf("Reps, Lapses, FirstReview = %d, %d, %v, want zero values", got.Reps, got.Lapses, got.FirstReview)
+	}
+	if got.State != Review || *got.Stability != 5 {
+		t.Errorf("older fields not read: %+v", got)
+	}
 }
 
 func TestCardJSONNilFields(t *testing.T) {
@@ -212,3 +250,36 @@ func searchSubstr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCardValidate(t *testing.T) {
+	valid := []Card{
+		NewCard(1),
+		{CardID: 2, State: Review, Stability: ptrF(3), Difficulty: ptrF(10), LastReview: ptrT(t0)},
+		{CardID: 3, State: Relearning, Step: ptrI(2), Stability: ptrF(0.001), Difficulty: ptrF(1), Queue: QueueBuried},
+	}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("card %d: Validate() = %v, want nil", c.CardID, err)
+		}
+	}
+
+	invalid := map[string]Card{
+		"state":          {State: State(0)},
+		"queue":          {State: Learning, Queue: Queue(3)},
+		"no difficulty":  {State: Review, Stability: ptrF(3)},
+		"no stability":   {State: Review, Difficulty: ptrF(5)},
+		"zero stability": {State: Review, Stability: ptrF(0), Difficulty: ptrF(5)},
+		"NaN stability":  {State: Review, Stability: ptrF(math.NaN()), Difficulty: ptrF(5)},
+		"inf stability":  {State: Review, Stability: ptrF(math.Inf(1)), Difficulty: ptrF(5)},
+		"low difficulty": {State: Review, Stability: ptrF(3), Difficulty: ptrF(0.5)},
+		"NaN difficulty": {State: Review, Stability: ptrF(3), Difficulty: ptrF(math.NaN())},
+		"negative step":  {State: Learning, Step: ptrI(-1)},
+		"negative reps":  {State: Learning, Reps: -1},
+		"negative lapse": {State: Learning, Lapses: -1},
+	}
+	for name, c := range invalid {
+		if err := c.Validate(); !errors.Is(err, ErrInvalidCard) {
+			t.Errorf("%s: Validate() = %v, want ErrInvalidCard", name, err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package flux
+
+import "time"
+
+// Clock supplies the current time to the parts of the package that need a
+// default "now": Scheduler.NewCard, time-derived fuzz seeds and the
+// reference time of RescheduleCardWith. Set SchedulerConfig.Clock to a fixed
+// or simulated clock to make them deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now calls f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// systemClock is the wall clock, used when no Clock is configured.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,80 @@
+package flux
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func fixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+func TestNewCardAt(t *testing.T) {
+	c := NewCardAt(7, t0)
+	if c.CardID != 7 || c.State != Learning || *c.Step != 0 || !c.Due.Equal(t0) {
+		t.Errorf("NewCardAt(7, t0) = %+v", c)
+	}
+}
+
+func TestSchedulerNewCardUsesClock(t *testing.T) {
+	cfg := noFuzzCfg()
+	cfg.Clock = fixedClock(t0)
+	s := mustScheduler(t, cfg)
+	if c := s.NewCard(1); !c.Due.Equal(t0) {
+		t.Errorf("NewCard Due = %v, want %v", c.Due, t0)
+	}
+}
+
+func TestClockSeedsFuzz(t *testing.T) {
+	cfg := SchedulerConfig{Clock: fixedClock(t0)}
+	a, b := mustScheduler(t, cfg), mustScheduler(t, cfg)
+	if a.seed != t0.UnixNano() || a.seed != b.seed {
+		t.Errorf("seeds = %d, %d, want %d", a.seed, b.seed, t0.UnixNano())
+	}
+}
+
+func TestSystemClock(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	before := time.Now()
+	got := s.NewCard(1).Due
+	if got.Before(before) || got.After(time.Now()) {
+		t.Errorf("NewCard Due = %v, want about %v", got, before)
+	}
+}
+
+func TestClockReplayReference(t *testing.T) {
+	cfg := noFuzzCfg()
+	cfg.Clock = fixedClock(t0.Add(time.Hour))
+	s := mustScheduler(t, cfg)
+	logs := []ReviewLog{{CardID: 1, Rating: Good, ReviewDatetime: t0.Add(2 * time.Hour)}}
+	_, report, err := s.RescheduleCardWith(NewCardAt(1, t0), logs, ReplayOptions{Lenient: true})
+	if err != nil || report.Future != 1 {
+		t.Errorf("report, err = %+v, %v, want one future log", report, err)
+	}
+}
+
+func TestSchedulerJSONKeepsClock(t *testing.T) {
+	cfg := noFuzzCfg()
+	cfg.Clock = fixedClock(t0)
+	data, err := json.Marshal(mustScheduler(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fresh Scheduler
+	if err := json.Unmarshal(data, &fresh); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fresh.clock.(systemClock); !ok {
+		t.Errorf("unmarshaled clock = %T, want systemClock", fresh.clock)
+	}
+
+	kept := mustScheduler(t, cfg)
+	if err := json.Unmarshal(data, kept); err != nil {
+		t.Fatal(err)
+	}
+	if c := kept.NewCard(1); !c.Due.Equal(t0) {
+		t.Errorf("NewCard Due after Unmarshal = %v, want %v", c.Due, t0)
+	}
+}
@@ -0,0 +1,69 @@
+package flux
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// These tests are meant to be run with -race: they share one Scheduler
+// across goroutines and fail under the race detector if any method mutates
+// shared state without synchronization.
+
+func runConcurrently(t *testing.T, s *Scheduler) {
+	t.Helper()
+	const goroutines = 16
+	t1 := t0.Add(10 * 24 * time.Hour)
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func(g int) {
+			defer wg.Done()
+			card := reviewCard(t)
+			card.CardID = int64(g)
+			for i := 0; i < 50; i++ {
+				c, log := s.ReviewCard(card, Good, t1)
+				if c.Due.Before(t1) {
+					t.Errorf("goroutine %d: Due %v before review time", g, c.Due)
+				}
+				s.PreviewCard(card, t1)
+				if _, err := s.RescheduleCard(card, []ReviewLog{log}); err != nil {
+					t.Errorf("goroutine %d: RescheduleCard: %v", g, err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestSchedulerConcurrentRandomFuzz(t *testing.T) {
+	runConcurrently(t, mustScheduler(t, SchedulerConfig{Seed: 1}))
+}
+
+func TestSchedulerConcurrentCardSeededFuzz(t *testing.T) {
+	runConcurrently(t, mustScheduler(t, SchedulerConfig{FuzzMode: FuzzCardSeeded}))
+}
+
+func TestSchedulerConcurrentDrawsDistinct(t *testing.T) {
+	// Concurrent callers share one stream; each draw consumes its own index,
+	// so the total number of draws is exact.
+	s := mustScheduler(t, SchedulerConfig{Seed: 1})
+	const goroutines, draws = 8, 100
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for g := 0; g < goroutines; g++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < draws; i++ {
+				s.fuzzFactor(Card{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := s.draws.Load(); got != goroutines*draws {
+		t.Errorf("draws = %d, want %d", got, goroutines*draws)
+	}
+}
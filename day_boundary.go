@@ -0,0 +1,65 @@
+package flux
+
+import "time"
+
+// learnerDay returns the date of the learner's day containing t, as
+// midnight UTC so that differences between days are exact multiples of 24h.
+// A day runs from dayStartHour in the Scheduler's location to the same hour
+// the next calendar day. Only valid when a location is set.
+func (s *Scheduler) learnerDay(t time.Time) time.Time {
+	// Compare clock hours, not elapsed time, so days that gain or lose an
+	// hour to daylight saving still roll over at dayStartHour.
+	l := t.In(s.location)
+	y, m, d := l.Date()
+	if l.Hour() < s.dayStartHour {
+		d--
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// elapsedDays returns the days between two reviews: fractional days, or
+// whole learner days when a location is set, as in Anki.
+func (s *Scheduler) elapsedDays(last, now time.Time) float64 {
+	if s.location == nil {
+		return now.Sub(last).Hours() / 24.0
+	}
+	return s.learnerDay(now).Sub(s.learnerDay(last)).Hours() / 24.0
+}
+
+// dueAt returns the due date for an interval of days from now: exactly
+// days×24h later, or the start of the learner's day that many days after
+// today when a location is set.
+func (s *Scheduler) dueAt(now time.Time, days int) time.Time {
+	if s.location == nil {
+		return now.Add(time.Duration(days) * 24 * time.Hour)
+	}
+	d := s.learnerDay(now)
+	return time.Date(d.Year(), d.Month(), d.Day()+days, s.dayStartHour, 0, 0, 0, s.location)
+}
+
+// zoneProbes are the instants at which locations are compared: midwinter and
+// midsummer, so zones with different daylight saving rules disagree.
+var zoneProbes = [...]time.Time{
+	time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC),
+	time.Date(2025, time.July, 15, 12, 0, 0, 0, time.UTC),
+}
+
+// sameOffsets reports whether a and b have the same UTC offset at every
+// zone probe.
+func sameOffsets(a, b *time.Location) bool {
+	for _, t := range zoneProbes {
+		_, oa := t.In(a).Zone()
+		_, ob := t.In(b).Zone()
+		if oa != ob {
+			return false
+		}
+	}
+	return true
+}
+
+// fixedOffset returns loc's offset in seconds east of UTC, and whether it
+// is the same at every zone probe.
+func fixedOffset(loc *time.Location) (int, bool) {
+	_, offset := zoneProbes[0].In(loc).Zone()
+	return offset, sameOffsets(loc, time.FixedZone("", offset))
+}
@@ -0,0 +1,318 @@
+package flux
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // America/New_York for the daylight saving tests
+)
+
+// est is a fixed UTC-5 zone; learners' days in these tests start at 04:00.
+var est = time.FixedZone("EST", -5*60*60)
+
+func dayCfg() SchedulerConfig {
+	return SchedulerConfig{DisableFuzzing: true, Location: est, DayStartHour: 4}
+}
+
+func TestNewSchedulerInvalidDayStartHour(t *testing.T) {
+	for _, h := range []int{-1, 24} {
+		_, err := NewScheduler(SchedulerConfig{Location: est, DayStartHour: h})
+		if err == nil || !strings.Contains(err.Error(), "day start hour") {
+			t.Errorf("DayStartHour=%d: err = %v, want range error", h, err)
+		}
+	}
+}
+
+func TestNewSchedulerDayStartHourWithoutLocation(t *testing.T) {
+	_, err := NewScheduler(SchedulerConfig{DayStartHour: 4})
+	if err == nil || !strings.Contains(err.Error(), "requires a Location") {
+		t.Errorf("DayStartHour without Location: err = %v, want error", err)
+	}
+}
+
+func TestLearnerDay(t *testing.T) {
+	s := mustScheduler(t, dayCfg())
+	tests := []struct {
+		local time.Time
+		want  time.Time
+	}{
+		{time.Date(2025, 6, 15, 3, 59, 0, 0, est), time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 6, 15, 4, 0, 0, 0, est), time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 6, 15, 23, 0, 0, 0, est), time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 6, 16, 2, 0, 0, 0, time.UTC), time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)}, // 21:00 EST
+	}
+	for _, tt := range tests {
+		if got := s.learnerDay(tt.local); !got.Equal(tt.want) {
+			t.Errorf("learnerDay(%v) = %v, want %v", tt.local, got, tt.want)
+		}
+	}
+}
+
+func TestLearnerDayDaylightSaving(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := mustScheduler(t, SchedulerConfig{DisableFuzzing: true, Location: ny, DayStartHour: 4})
+	tests := []struct {
+		local time.Time
+		want  time.Time
+	}{
+		// Spring forward: 2025-03-09 02:00 EST → 03:00 EDT.
+		{time.Date(2025, 3, 9, 3, 30, 0, 0, ny), time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 3, 9, 4, 30, 0, 0, ny), time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)},
+		// Fall back: 2025-11-02 02:00 EDT → 01:00 EST.
+		{time.Date(2025, 11, 2, 3, 30, 0, 0, ny), time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 11, 2, 4, 30, 0, 0, ny), time.Date(2025, 11, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := s.learnerDay(tt.local); !got.Equal(tt.want) {
+			t.Errorf("learnerDay(%v) = %v, want %v", tt.local, got, tt.want)
+		}
+	}
+}
+
+func TestReviewCardDayBoundarySpringForward(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := mustScheduler(t, SchedulerConfig{DisableFuzzing: true, Location: ny, DayStartHour: 4})
+
+	// Graduating between 04:00 and 05:00 on the spring-forward day is due
+	// at the start of a later learner day, never before the review.
+	now := time.Date(2025, 3, 9, 4, 30, 0, 0, ny)
+	c, _ := s.ReviewCard(NewCardAt(1, now), Easy, now)
+	if c.State != Review {
+		t.Fatalf("state = %v, want Review", c.State)
+	}
+	if due := c.Due.In(ny); !due.After(now) || due.Hour() != 4 || due.Day() == 9 {
+		t.Errorf("Due = %v, want 04:00 on a later day than %v", due, now)
+	}
+
+	// The previous evening is one learner day earlier.
+	last := time.Date(2025, 3, 8, 22, 0, 0, 0, ny)
+	assertFloat(t, "elapsedDays", s.elapsedDays(last, now), 1)
+}
+
+func TestElapsedDaysCalendar(t *testing.T) {
+	s := mustScheduler(t, dayCfg())
+	last := time.Date(2025, 6, 15, 22, 0, 0, 0, est)
+	tests := []struct {
+		now  time.Time
+		want float64
+	}{
+		{time.Date(2025, 6, 16, 3, 0, 0, 0, est), 0}, // before rollover
+		{time.Date(2025, 6, 16, 5, 0, 0, 0, est), 1}, // 7h later, next day
+		{time.Date(2025, 6, 20, 23, 0, 0, 0, est), 5},
+	}
+	for _, tt := range tests {
+		assertFloat(t, "elapsedDays", s.elapsedDays(last, tt.now), tt.want)
+	}
+}
+
+func TestReviewCardDayBoundaryCrossDay(t *testing.T) {
+	// Seven hours apart but across the 04:00 rollover: a cross-day review
+	// with one elapsed day, where the default mode sees a same-day review.
+	last := time.Date(2025, 6, 15, 22, 0, 0, 0, est)
+	now := time.Date(2025, 6, 16, 5, 0, 0, 0, est)
+	card := reviewCard(t)
+	card.LastReview = ptrT(last)
+
+	s := mustScheduler(t, dayCfg())
+	got, _ := s.ReviewCard(card, Good, now)
+	r := s.algo.retrievability(1, 5)
+	assertFloat(t, "stability", *got.Stability, s.algo.nextStability(5, 5, r, Good))
+
+	plain := mustScheduler(t, noFuzzCfg())
+	same, _ := plain.ReviewCard(card, Good, now)
+	assertFloat(t, "default stability", *same.Stability, plain.algo.shortTermStability(5, Good))
+}
+
+// cardReviewedDaysAgo returns reviewCard last reviewed exactly days×24h
+// before now, so both modes see the same elapsed days.
+func cardReviewedDaysAgo(t *testing.T, now time.Time, days int) Card {
+	card := reviewCard(t)
+	card.LastReview = ptrT(now.Add(-time.Duration(days) * 24 * time.Hour))
+	return card
+}
+
+func TestReviewCardDayBoundarySnapsDue(t *testing.T) {
+	now := time.Date(2025, 6, 25, 23, 30, 0, 0, est)
+	card := cardReviewedDaysAgo(t, now, 10)
+	plain, _ := mustScheduler(t, noFuzzCfg()).ReviewCard(card, Good, now)
+	days := int(plain.Due.Sub(now).Hours() / 24)
+
+	got, _ := mustScheduler(t, dayCfg()).ReviewCard(card, Good, now)
+	want := time.Date(2025, 6, 25+days, 4, 0, 0, 0, est)
+	if !got.Due.Equal(want) {
+		t.Errorf("Due = %v, want start of learner day %v", got.Due, want)
+	}
+}
+
+func TestReviewCardDayBoundaryBeforeRollover(t *testing.T) {
+	// At 02:00 the learner is still on the previous day.
+	now := time.Date(2025, 6, 26, 2, 0, 0, 0, est)
+	card := cardReviewedDaysAgo(t, now, 10)
+	plain, _ := mustScheduler(t, noFuzzCfg()).ReviewCard(card, Good, now)
+	days := int(plain.Due.Sub(now).Hours() / 24)
+
+	got, _ := mustScheduler(t, dayCfg()).ReviewCard(card, Good, now)
+	want := time.Date(2025, 6, 25+days, 4, 0, 0, 0, est)
+	if !got.Due.Equal(want) {
+		t.Errorf("Due = %v, want %v", got.Due, want)
+	}
+}
+
+func TestReviewCardDayBoundaryLearningSteps(t *testing.T) {
+	now := time.Date(2025, 6, 25, 23, 30, 0, 0, est)
+	got, _ := mustScheduler(t, dayCfg()).ReviewCard(NewCard(1), Again, now)
+	if want := now.Add(time.Minute); !got.Due.Equal(want) {
+		t.Errorf("learning Due = %v, want %v (steps are not snapped)", got.Due, want)
+	}
+}
+
+func TestReviewCardDayBoundaryEasyDays(t *testing.T) {
+	// 23:30 EST is already the next UTC day; weekdays follow the learner's day.
+	now := time.Date(2025, 6, 25, 23, 30, 0, 0, est)
+	cfg := dayCfg()
+	plain, _ := mustScheduler(t, cfg).ReviewCard(reviewCard(t), Good, now)
+	off := plain.Due.In(est).Weekday()
+
+	cfg.WeekdayWeights = weekdaysOff(off)
+	got, _ := mustScheduler(t, cfg).ReviewCard(reviewCard(t), Good, now)
+	if got.Due.In(est).Weekday() == off {
+		t.Errorf("due on zero-capacity %s", off)
+	}
+	if got.Due.In(est).Hour() != 4 {
+		t.Errorf("Due = %v, want snapped to 04:00", got.Due)
+	}
+}
+
+func TestSchedulerJSONDayBoundary(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{Location: time.UTC, DayStartHour: 4})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var s2 Scheduler
+	if err := json.Unmarshal(data, &s2); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s2.location != time.UTC || s2.dayStartHour != 4 {
+		t.Errorf("round-trip location=%v hour=%d, want UTC 4", s2.location, s2.dayStartHour)
+	}
+
+	data, err = json.Marshal(mustScheduler(t, SchedulerConfig{}))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), `"location"`) {
+		t.Errorf("location serialized without day-boundary mode: %s", data)
+	}
+}
+
+func TestSchedulerUnmarshalJSONBadLocation(t *testing.T) {
+	var s Scheduler
+	err := json.Unmarshal([]byte(`{"location":"Nowhere/Invalid"}`), &s)
+	if err == nil || !strings.Contains(err.Error(), "location") {
+		t.Errorf("Unmarshal bad location: err = %v, want location error", err)
+	}
+}
+
+func TestRetrievabilityDayBoundary(t *testing.T) {
+	// Seven hours apart across the rollover: one whole learner day, as the
+	// review itself sees it.
+	last := time.Date(2025, 6, 15, 22, 0, 0, 0, est)
+	now := time.Date(2025, 6, 16, 5, 0, 0, 0, est)
+	card := reviewCard(t)
+	card.LastReview = ptrT(last)
+
+	cfg := dayCfg()
+	cfg.RecordSnapshots = true
+	s := mustScheduler(t, cfg)
+	_, log := s.ReviewCard(card, Good, now)
+	assertFloat(t, "Retrievability", s.Retrievability(card, now), s.algo.retrievability(1, 5))
+	assertFloat(t, "RetrievabilityBefore", log.Snapshot.RetrievabilityBefore, s.Retrievability(card, now))
+}
+
+func TestSnapshotDayBoundary(t *testing.T) {
+	// Reviewed late in the evening: the new due date is a whole number of
+	// learner days away, though fewer hours than that.
+	now := time.Date(2025, 6, 15, 22, 0, 0, 0, est)
+	card := reviewCard(t)
+	card.LastReview = ptrT(now.Add(-5 * 24 * time.Hour))
+
+	cfg := dayCfg()
+	cfg.RecordSnapshots = true
+	s := mustScheduler(t, cfg)
+	c, log := s.ReviewCard(card, Good, now)
+	if d := log.Snapshot.ScheduledDays; d != math.Trunc(d) || d < 1 {
+		t.Errorf("ScheduledDays = %v, want a whole number of learner days", d)
+	}
+	assertFloat(t, "RetrievabilityAfter", log.Snapshot.RetrievabilityAfter, s.Retrievability(c, c.Due))
+}
+
+func TestSchedulerJSONFixedZone(t *testing.T) {
+	for _, loc := range []*time.Location{
+		time.FixedZone("UTC+8", 8*60*60), // not a zone name
+		time.FixedZone("CET", 60*60),     // a zone name with different rules
+	} {
+		s := mustScheduler(t, SchedulerConfig{Location: loc, DayStartHour: 4})
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("Marshal %v: %v", loc, err)
+		}
+		var s2 Scheduler
+		if err := json.Unmarshal(data, &s2); err != nil {
+			t.Fatalf("Unmarshal %v: %v (%s)", loc, err, data)
+		}
+		if s2.location.String() != loc.String() || !sameOffsets(s2.location, loc) {
+			t.Errorf("round-trip location = %v, want %v", s2.location, loc)
+		}
+	}
+}
+
+func TestSchedulerJSONNamedZoneNoOffset(t *testing.T) {
+	data, err := json.Marshal(mustScheduler(t, SchedulerConfig{Location: time.UTC}))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), "location_offset") {
+		t.Errorf("offset serialized for a named zone: %s", data)
+	}
+}
+
+func TestSchedulerMarshalJSONUnnamedZone(t *testing.T) {
+	// A zone with daylight saving time that cannot be loaded by name.
+	loc, err := time.LoadLocationFromTZData("Custom/Zone", tzifWithDST())
+	if err != nil {
+		t.Fatalf("LoadLocationFromTZData: %v", err)
+	}
+	_, err = json.Marshal(mustScheduler(t, SchedulerConfig{Location: loc}))
+	if err == nil || !strings.Contains(err.Error(), "Custom/Zone") {
+		t.Errorf("Marshal unnamed zone: err = %v, want location error", err)
+	}
+}
+
+// tzifWithDST returns TZif data for a zone at UTC, except UTC+1 from March
+// to October 2025.
+func tzifWithDST() []byte {
+	b := append([]byte("TZif"), make([]byte, 16)...)
+	for _, n := range []uint32{0, 0, 0, 2, 2, 8} { // isut, isstd, leap, time, type, char counts
+		b = binary.BigEndian.AppendUint32(b, n)
+	}
+	for _, t := range []time.Time{
+		time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	}
+	b = append(b, 1, 0)                   // type of each transition
+	b = append(b, 0, 0, 0, 0, 0, 0)       // type 0: UTC, standard, "STD"
+	b = append(b, 0, 0, 0x0e, 0x10, 1, 4) // type 1: UTC+1, DST, "DST"
+	return append(b, "STD\x00DST\x00"...)
+}
@@ -0,0 +1,26 @@
+package flux
+
+import "time"
+
+// SetDueDate moves the card's due date to due, for example "review in 3
+// days" or "after vacation". Stability, Difficulty, State and LastReview are
+// kept, so the next review sees the true elapsed time. A buried card is
+// returned to rotation; a suspended card stays suspended. It returns the
+// updated card and a KindManual log, which RescheduleCard replays and the
+// optimizer skips. The input card is not mutated.
+func (s *Scheduler) SetDueDate(card Card, due, now time.Time) (Card, ReviewLog) {
+	c := card.clone()
+	c.Due = due
+	if c.Queue == QueueBuried {
+		c.Queue, c.BuriedUntil = QueueActive, nil
+	}
+
+	log := ReviewLog{
+		CardID:         c.CardID,
+		CardKey:        c.Key,
+		ReviewDatetime: now,
+		Kind:           KindManual,
+		Due:            &due,
+	}
+	return c, log
+}
@@ -0,0 +1,89 @@
+package flux
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSetDueDate(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	orig := reviewCard(t)
+	now := t0.Add(2 * 24 * time.Hour)
+	due := t0.Add(20 * 24 * time.Hour)
+
+	c, log := s.SetDueDate(orig, due, now)
+	if !c.Due.Equal(due) {
+		t.Errorf("Due = %v, want %v", c.Due, due)
+	}
+	if c.State != orig.State || *c.Stability != *orig.Stability || *c.Difficulty != *orig.Difficulty ||
+		!c.LastReview.Equal(*orig.LastReview) || c.Reps != orig.Reps {
+		t.Errorf("SetDueDate changed memory state: %+v, want %+v", c, orig)
+	}
+	if !orig.Due.Equal(t0) {
+		t.Error("SetDueDate mutated its input")
+	}
+	if log.Kind != KindManual || log.Rating != 0 || !log.ReviewDatetime.Equal(now) || !log.Due.Equal(due) {
+		t.Errorf("log = %+v, want KindManual at %v with Due %v", log, now, due)
+	}
+}
+
+func TestSetDueDateQueue(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	due := t0.Add(5 * 24 * time.Hour)
+	c, _ := s.SetDueDate(s.BuryCard(reviewCard(t), t0), due, t0)
+	if c.Queue != QueueActive || c.BuriedUntil != nil {
+		t.Errorf("buried card: Queue, BuriedUntil = %v, %v, want Active, nil", c.Queue, c.BuriedUntil)
+	}
+	c, _ = s.SetDueDate(s.SuspendCard(reviewCard(t)), due, t0)
+	if c.Queue != QueueSuspended {
+		t.Errorf("suspended card: Queue = %v, want Suspended", c.Queue)
+	}
+}
+
+func TestSetDueDateNextReviewUsesLastReview(t *testing.T) {
+	// Moving the due date does not reset the elapsed time seen by the next review.
+	s := mustScheduler(t, noFuzzCfg())
+	moved, _ := s.SetDueDate(reviewCard(t), t0.Add(20*24*time.Hour), t0.Add(24*time.Hour))
+	t1 := t0.Add(20 * 24 * time.Hour)
+	got, _ := s.ReviewCard(moved, Good, t1)
+	want, _ := s.ReviewCard(reviewCard(t), Good, t1)
+	assertFloat(t, "Stability", *got.Stability, *want.Stability)
+}
+
+func TestManualLogJSON(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	_, log := s.SetDueDate(reviewCard(t), t0.Add(72*time.Hour), t0)
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got ReviewLog
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Kind != KindManual || !got.Due.Equal(*log.Due) {
+		t.Errorf("round-trip = %+v, want %+v", got, log)
+	}
+}
+
+func TestRescheduleCardReplaysSetDueDate(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := NewCard(1)
+	var logs []ReviewLog
+	var log ReviewLog
+	c, log = s.ReviewCard(c, Easy, t0)
+	logs = append(logs, log)
+	c, log = s.SetDueDate(c, t0.Add(40*24*time.Hour), t0.Add(24*time.Hour))
+	logs = append(logs, log)
+	// A manual log without a due date is ignored.
+	logs = append(logs, ReviewLog{CardID: 1, Kind: KindManual, ReviewDatetime: t0.Add(25 * time.Hour)})
+
+	got, err := s.RescheduleCard(NewCard(1), logs)
+	if err != nil {
+		t.Fatalf("RescheduleCard: %v", err)
+	}
+	if !got.Due.Equal(c.Due) || got.Reps != 1 || !got.LastReview.Equal(t0) {
+		t.Errorf("replayed Due, Reps, LastReview = %v, %d, %v, want %v, 1, %v", got.Due, got.Reps, got.LastReview, c.Due, t0)
+	}
+}
@@ -0,0 +1,47 @@
+package flux
+
+import (
+	"fmt"
+	"time"
+)
+
+// validateWeekdayWeights checks that every weekday weight lies in [0, 1].
+func validateWeekdayWeights(w [7]float64) error {
+	for d, v := range w {
+		if v < 0 || v > 1 {
+			return fmt.Errorf("flux: weekday weight for %s = %f out of range [0, 1]", time.Weekday(d), v)
+		}
+	}
+	return nil
+}
+
+// easiestDay returns the day d in the fuzz range of interval, bounded to
+// [minIvl, maxIvl], with the highest capacity(d). Ties go to the day closest
+// to interval, then to the shorter one, so uniform capacity leaves the
+// interval unchanged.
+// Returns the interval unchanged (raised to minIvl) if < 2.5 days.
+func easiestDay(interval, minIvl, maxIvl int, capacity func(d int) float64) int {
+	interval, small := smallInterval(interval, minIvl, maxIvl)
+	if small {
+		return interval
+	}
+
+	lo, hi := fuzzRange(interval, minIvl, maxIvl)
+	best := lo
+	bestWeight := -1.0
+	for d := lo; d <= hi; d++ {
+		w := capacity(d)
+		closer := abs(d-interval) < abs(best-interval)
+		if w > bestWeight || (w == bestWeight && closer) {
+			best, bestWeight = d, w
+		}
+	}
+	return best
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
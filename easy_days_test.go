@@ -0,0 +1,162 @@
+package flux
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// weekdaysOff returns weekday weights of 1 with the given days set to 0.
+func weekdaysOff(days ...time.Weekday) [7]float64 {
+	w := [7]float64{1, 1, 1, 1, 1, 1, 1}
+	for _, d := range days {
+		w[d] = 0
+	}
+	return w
+}
+
+// byWeekday returns an easiestDay capacity function reading w for days
+// counted from t0.
+func byWeekday(w [7]float64) func(int) float64 {
+	return func(d int) float64 {
+		return w[t0.Add(time.Duration(d)*24*time.Hour).Weekday()]
+	}
+}
+
+// --- validation ---
+
+func TestNewSchedulerInvalidWeekdayWeights(t *testing.T) {
+	for _, v := range []float64{-0.1, 1.5} {
+		w := weekdaysOff()
+		w[time.Saturday] = v
+		_, err := NewScheduler(SchedulerConfig{WeekdayWeights: w})
+		if err == nil || !strings.Contains(err.Error(), "Saturday") {
+			t.Errorf("WeekdayWeights[Saturday]=%v: err = %v, want range error naming Saturday", v, err)
+		}
+	}
+}
+
+func TestNewSchedulerWeekdayWeightsDefault(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{})
+	if s.weekdayWeights != weekdaysOff() || s.easyDays {
+		t.Errorf("default weekday weights = %v (easyDays %v), want all 1", s.weekdayWeights, s.easyDays)
+	}
+	s = mustScheduler(t, SchedulerConfig{WeekdayWeights: [7]float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5}})
+	if s.easyDays {
+		t.Error("uniform weekday weights should not enable easy days")
+	}
+}
+
+// --- easiestDay ---
+
+func TestEasiestDaySmallInterval(t *testing.T) {
+	if got := easiestDay(2, 1, 36500, byWeekday(weekdaysOff(time.Tuesday))); got != 2 {
+		t.Errorf("easiestDay(2) = %d, want 2", got)
+	}
+}
+
+func TestEasiestDayUniform(t *testing.T) {
+	if got := easiestDay(10, 1, 36500, byWeekday(weekdaysOff())); got != 10 {
+		t.Errorf("easiestDay(10) with uniform weights = %d, want 10", got)
+	}
+}
+
+func TestEasiestDayPrefersCapacity(t *testing.T) {
+	// t0 is a Sunday; interval=10 → range [8, 12] = Monday..Friday.
+	w := [7]float64{1, 0.2, 0.2, 0.5, 0.2, 0.8, 1}
+	if got := easiestDay(10, 1, 36500, byWeekday(w)); got != 12 {
+		t.Errorf("easiestDay = %d, want 12 (Friday)", got)
+	}
+}
+
+func TestEasiestDayTieBreak(t *testing.T) {
+	// Wednesday (day 10) is off; Tuesday (9) and Thursday (11) tie on
+	// weight and distance, so the shorter interval wins.
+	if got := easiestDay(10, 1, 36500, byWeekday(weekdaysOff(time.Wednesday))); got != 9 {
+		t.Errorf("easiestDay = %d, want 9", got)
+	}
+}
+
+// --- weightedFuzz ---
+
+func TestWeightedFuzzZeroWeights(t *testing.T) {
+	zero := func(int) float64 { return 0 }
+	for _, u := range []float64{0, 0.3, 0.7, 0.99} {
+		if got, want := weightedFuzz(10, 1, 36500, u, zero), applyFuzz(10, 1, 36500, u); got != want {
+			t.Errorf("weightedFuzz(u=%v) with zero weights = %d, want applyFuzz %d", u, got, want)
+		}
+	}
+}
+
+// --- Scheduler ---
+
+func TestSchedulerEasyDaysNoFuzz(t *testing.T) {
+	t1 := t0.Add(10 * 24 * time.Hour)
+	plain := mustScheduler(t, noFuzzCfg())
+	want, _ := plain.ReviewCard(reviewCard(t), Good, t1)
+
+	off := want.Due.Weekday()
+	cfg := noFuzzCfg()
+	cfg.WeekdayWeights = weekdaysOff(off)
+	s := mustScheduler(t, cfg)
+	for i := 0; i < 3; i++ {
+		got, _ := s.ReviewCard(reviewCard(t), Good, t1)
+		if got.Due.Weekday() == off {
+			t.Fatalf("due on %s, which has zero capacity", off)
+		}
+		if d := got.Due.Sub(want.Due); d != 24*time.Hour && d != -24*time.Hour {
+			t.Errorf("due moved by %v, want one day", d)
+		}
+	}
+}
+
+func TestSchedulerEasyDaysFuzz(t *testing.T) {
+	t1 := t0.Add(10 * 24 * time.Hour)
+	s := mustScheduler(t, SchedulerConfig{Seed: 11, WeekdayWeights: weekdaysOff(time.Saturday, time.Sunday)})
+	for i := 0; i < 50; i++ {
+		c, _ := s.ReviewCard(reviewCard(t), Good, t1)
+		if wd := c.Due.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			t.Fatalf("review %d: due on %s, a zero-capacity day", i, wd)
+		}
+	}
+}
+
+func TestSchedulerEasyDaysWithLoadBalancer(t *testing.T) {
+	t1 := t0.Add(10 * 24 * time.Hour)
+	s := mustScheduler(t, SchedulerConfig{Seed: 3, DisableFuzzing: true})
+	plain, _ := s.ReviewCard(reviewCard(t), Good, t1)
+	ivl := int(plain.Due.Sub(t1).Hours() / 24)
+	_, hi := fuzzRange(ivl, 1, 36500)
+
+	// The emptiest day is on a zero-capacity weekday, so it is never chosen.
+	free := t1.Add(time.Duration(hi) * 24 * time.Hour)
+	s = mustScheduler(t, SchedulerConfig{
+		Seed:           3,
+		LoadBalancer:   busyExcept(t1, hi),
+		WeekdayWeights: weekdaysOff(free.Weekday()),
+	})
+	for i := 0; i < 20; i++ {
+		c, _ := s.ReviewCard(reviewCard(t), Good, t1)
+		if c.Due.Equal(free) {
+			t.Fatalf("review %d: due on zero-capacity %s", i, free.Weekday())
+		}
+	}
+}
+
+func TestSchedulerJSONWeekdayWeights(t *testing.T) {
+	w := weekdaysOff(time.Sunday)
+	w[time.Saturday] = 0.5
+	s := mustScheduler(t, SchedulerConfig{WeekdayWeights: w})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var s2 Scheduler
+	if err := json.Unmarshal(data, &s2); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s2.weekdayWeights != w || !s2.easyDays {
+		t.Errorf("round-trip weekday weights = %v (easyDays %v), want %v", s2.weekdayWeights, s2.easyDays, w)
+	}
+}
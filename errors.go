@@ -9,4 +9,9 @@ var (
 	ErrInvalidParameters = errors.New("flux: parameters out of bounds")
 	ErrCardIDMismatch    = errors.New("flux: card ID mismatch in review log")
 	ErrInsufficientData  = errors.New("flux: insufficient review data for optimization")
+	ErrLogOutOfOrder     = errors.New("flux: review log out of chronological order")
+	ErrDuplicateLog      = errors.New("flux: duplicate review log")
+	ErrFutureLog         = errors.New("flux: review log dated in the future")
+	ErrInvalidCard       = errors.New("flux: invalid card")
+	ErrTimeTravel        = errors.New("flux: review time before last review")
 )
@@ -12,6 +12,11 @@ func TestSentinelErrors(t *testing.T) {
 		ErrInvalidParameters,
 		ErrCardIDMismatch,
 		ErrInsufficientData,
+		ErrLogOutOfOrder,
+		ErrDuplicateLog,
+		ErrFutureLog,
+		ErrInvalidCard,
+		ErrTimeTravel,
 	}
 	for _, err := range sentinels {
 		if err == nil {
@@ -40,6 +45,11 @@ func TestSentinelErrorPrefix(t *testing.T) {
 		{ErrInvalidParameters, "flux: "},
 		{ErrCardIDMismatch, "flux: "},
 		{ErrInsufficientData, "flux: "},
+		{ErrLogOutOfOrder, "flux: "},
+		{ErrDuplicateLog, "flux: "},
+		{ErrFutureLog, "flux: "},
+		{ErrInvalidCard, "flux: "},
+		{ErrTimeTravel, "flux: "},
 	}
 	for _, tt := range tests {
 		msg := tt.err.Error()
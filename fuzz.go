@@ -1,10 +1,93 @@
 package flux
 
 import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"math"
-	"math/rand"
 )
 
+// FuzzMode selects where the random value used for interval fuzzing comes from.
+type FuzzMode int
+
+const (
+	// FuzzRandom draws from the Scheduler's seeded random stream. Every call
+	// draws anew, so a preview may differ from the review that follows it.
+	// Concurrent callers draw distinct values without locking.
+	FuzzRandom FuzzMode = iota
+	// FuzzCardSeeded derives the value from the card's CardID, Key and LastReview,
+	// like Anki. Previews, reviews and replays of the same card at the same
+	// point in its history always agree, across Scheduler instances.
+	FuzzCardSeeded
+)
+
+var (
+	fuzzModeNames  = [...]string{FuzzRandom: "Random", FuzzCardSeeded: "CardSeeded"}
+	fuzzModeByName = map[string]FuzzMode{
+		"Random":     FuzzRandom,
+		"CardSeeded": FuzzCardSeeded,
+	}
+)
+
+// Compile-time interface checks.
+var (
+	_ fmt.Stringer             = FuzzMode(0)
+	_ json.Marshaler           = FuzzMode(0)
+	_ json.Unmarshaler         = (*FuzzMode)(nil)
+	_ encoding.TextMarshaler   = FuzzMode(0)
+	_ encoding.TextUnmarshaler = (*FuzzMode)(nil)
+)
+
+func (m FuzzMode) isValid() bool {
+	return m >= FuzzRandom && m <= FuzzCardSeeded
+}
+
+// String returns the name of the fuzz mode ("Random", "CardSeeded").
+// For invalid values it returns "FuzzMode(n)".
+func (m FuzzMode) String() string {
+	if m.isValid() {
+		return fuzzModeNames[m]
+	}
+	return fmt.Sprintf("FuzzMode(%d)", int(m))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m FuzzMode) MarshalText() ([]byte, error) {
+	if !m.isValid() {
+		return nil, fmt.Errorf("flux: invalid fuzz mode: %d", int(m))
+	}
+	return []byte(fuzzModeNames[m]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (m *FuzzMode) UnmarshalText(text []byte) error {
+	v, ok := fuzzModeByName[string(text)]
+	if !ok {
+		return fmt.Errorf("flux: invalid fuzz mode: %q", text)
+	}
+	*m = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. FuzzMode serializes as a JSON string.
+func (m FuzzMode) MarshalJSON() ([]byte, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Expects a JSON string.
+func (m *FuzzMode) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("flux: invalid fuzz mode: %s", data)
+	}
+	return m.UnmarshalText([]byte(str))
+}
+
 type fuzzEntry struct {
 	start, end float64
 	factor     float64
@@ -26,21 +109,109 @@ func fuzzDelta(interval float64) float64 {
 	return delta
 }
 
-// applyFuzz randomizes the interval to prevent review clustering.
-// Returns the original interval unchanged if < 2.5 days.
-func applyFuzz(interval, maxIvl int, rng *rand.Rand) int {
-	if float64(interval) < 2.5 {
-		return interval
-	}
-
-	ivl := float64(interval)
+// fuzzRange returns the inclusive day range [lo, hi] that fuzzing may pick
+// from for the given interval, bounded to [minIvl, maxIvl]. An interval below
+// minIvl is raised to it first.
+func fuzzRange(interval, minIvl, maxIvl int) (lo, hi int) {
+	minIvl = min(minIvl, maxIvl)
+	ivl := float64(max(interval, minIvl))
 	delta := fuzzDelta(ivl)
 
-	minIvl := max(2, int(math.Round(ivl-delta)))
-	maxFuzzIvl := min(int(math.Round(ivl+delta)), maxIvl)
-	minIvl = min(minIvl, maxFuzzIvl)
+	lo = max(2, int(math.Round(ivl-delta)))
+	hi = min(int(math.Round(ivl+delta)), maxIvl)
+	lo = min(lo, hi)
+	return max(lo, minIvl), max(hi, minIvl)
+}
+
+// smallInterval reports whether interval, once raised to minIvl, is too
+// short to fuzz, returning the raised interval.
+func smallInterval(interval, minIvl, maxIvl int) (int, bool) {
+	ivl := max(interval, min(minIvl, maxIvl))
+	return ivl, float64(ivl) < 2.5
+}
+
+// applyFuzz randomizes the interval to prevent review clustering, within
+// [minIvl, maxIvl]. u is a uniform random value in [0, 1).
+// Returns the interval unchanged (raised to minIvl) if < 2.5 days.
+func applyFuzz(interval, minIvl, maxIvl int, u float64) int {
+	if ivl, small := smallInterval(interval, minIvl, maxIvl); small {
+		return ivl
+	}
+
+	lo, hi := fuzzRange(interval, minIvl, maxIvl)
 
-	fuzzed := int(math.Round(rng.Float64()*float64(maxFuzzIvl-minIvl+1))) + minIvl
+	fuzzed := int(math.Round(u*float64(hi-lo+1))) + lo
 	fuzzed = min(fuzzed, maxIvl)
 	return fuzzed
 }
+
+// weightedFuzz picks a day in the fuzz range of interval with probability
+// proportional to weight(d). u selects the day from the weighted
+// distribution, so the pick is as deterministic as u. If every candidate
+// has zero weight it falls back to applyFuzz.
+// Returns the interval unchanged (raised to minIvl) if < 2.5 days.
+func weightedFuzz(interval, minIvl, maxIvl int, u float64, weight func(d int) float64) int {
+	if ivl, small := smallInterval(interval, minIvl, maxIvl); small {
+		return ivl
+	}
+
+	lo, hi := fuzzRange(interval, minIvl, maxIvl)
+	weights := make([]float64, hi-lo+1)
+	var total float64
+	for i := range weights {
+		weights[i] = weight(lo + i)
+		total += weights[i]
+	}
+	if total == 0 {
+		return applyFuzz(interval, minIvl, maxIvl, u)
+	}
+
+	target := u * total
+	for i, w := range weights {
+		target -= w
+		if target < 0 {
+			return lo + i
+		}
+	}
+	return hi
+}
+
+// cardFuzzFactor derives a uniform value in [0, 1) from the card's identity
+// and the time of its previous review.
+func cardFuzzFactor(c Card) float64 {
+	x := uint64(c.CardID) * golden64
+	if c.Key != "" {
+		h := fnv.New64a()
+		h.Write([]byte(c.Key))
+		x ^= mix64(h.Sum64())
+	}
+	if c.LastReview != nil {
+		x ^= uint64(c.LastReview.UnixNano())
+	}
+	return unitFloat(mix64(x))
+}
+
+// streamFuzzFactor returns the n-th value of the uniform stream for seed.
+// Each value depends only on (seed, n), so concurrent callers need nothing
+// more than an atomic counter to draw distinct values.
+func streamFuzzFactor(seed int64, n uint64) float64 {
+	return unitFloat(mix64(uint64(seed) + n*golden64))
+}
+
+// golden64 is 2^64 divided by the golden ratio, the splitmix64 increment.
+const golden64 = 0x9e3779b97f4a7c15
+
+// mix64 is the splitmix64 finalizer: a bijective, well-distributed hash of x.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// unitFloat maps the top 53 bits of x to a float64 in [0, 1).
+func unitFloat(x uint64) float64 {
+	return float64(x>>11) / (1 << 53)
+}
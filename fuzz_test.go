@@ -1,8 +1,10 @@
 package flux
 
 import (
+	"encoding/json"
 	"math/rand"
 	"testing"
+	"time"
 )
 
 func TestFuzzDeltaSingleBand(t *testing.T) {
@@ -34,10 +36,10 @@ func TestFuzzDeltaThreeBands(t *testing.T) {
 func TestApplyFuzzNoFuzzSmallInterval(t *testing.T) {
 	rng := rand.New(rand.NewSource(42))
 	// interval < 2.5 → no fuzz, return as-is
-	if got := applyFuzz(1, 36500, rng); got != 1 {
+	if got := applyFuzz(1, 1, 36500, rng.Float64()); got != 1 {
 		t.Errorf("applyFuzz(1) = %d, want 1", got)
 	}
-	if got := applyFuzz(2, 36500, rng); got != 2 {
+	if got := applyFuzz(2, 1, 36500, rng.Float64()); got != 2 {
 		t.Errorf("applyFuzz(2) = %d, want 2", got)
 	}
 }
@@ -50,7 +52,7 @@ func TestApplyFuzzWithinBounds(t *testing.T) {
 	// formula: round(rand()*(12-8+1)+8) can produce up to 13
 	// final: min(13, 36500) = 13
 	for i := 0; i < 100; i++ {
-		got := applyFuzz(10, 36500, rng)
+		got := applyFuzz(10, 1, 36500, rng.Float64())
 		if got < 8 || got > 13 {
 			t.Errorf("applyFuzz(10) = %d, expected [8, 13]", got)
 		}
@@ -66,9 +68,9 @@ func TestApplyFuzzMaxIvlClamp(t *testing.T) {
 	// min_ivl = min(46, 48) = 46
 	// result should be in [46, 48]
 	for i := 0; i < 100; i++ {
-		got := applyFuzz(50, 48, rng)
+		got := applyFuzz(50, 1, 48, rng.Float64())
 		if got < 46 || got > 48 {
-			t.Errorf("applyFuzz(50, maxIvl=48) = %d, expected [46, 48]", got)
+			t.Errorf("applyFuzz(50, 1, maxIvl=48) = %d, expected [46, 48]", got)
 		}
 	}
 }
@@ -78,8 +80,8 @@ func TestApplyFuzzReproducible(t *testing.T) {
 	rng1 := rand.New(rand.NewSource(123))
 	rng2 := rand.New(rand.NewSource(123))
 	for i := 0; i < 20; i++ {
-		a := applyFuzz(15, 36500, rng1)
-		b := applyFuzz(15, 36500, rng2)
+		a := applyFuzz(15, 1, 36500, rng1.Float64())
+		b := applyFuzz(15, 1, 36500, rng2.Float64())
 		if a != b {
 			t.Errorf("iteration %d: %d != %d with same seed", i, a, b)
 		}
@@ -94,7 +96,7 @@ func TestApplyFuzzInterval3(t *testing.T) {
 	// formula: round(rand()*(4-2+1)+2) can produce up to 5
 	// final: min(5, 36500) = 5
 	for i := 0; i < 100; i++ {
-		got := applyFuzz(3, 36500, rng)
+		got := applyFuzz(3, 1, 36500, rng.Float64())
 		if got < 2 || got > 5 {
 			t.Errorf("applyFuzz(3) = %d, expected [2, 5]", got)
 		}
@@ -105,12 +107,117 @@ func TestApplyFuzzNeverExceedsMaxIvl(t *testing.T) {
 	rng := rand.New(rand.NewSource(99))
 	maxIvl := 10
 	for i := 0; i < 200; i++ {
-		got := applyFuzz(8, maxIvl, rng)
+		got := applyFuzz(8, 1, maxIvl, rng.Float64())
 		if got > maxIvl {
-			t.Errorf("applyFuzz(8, max=%d) = %d, exceeds max", maxIvl, got)
+			t.Errorf("applyFuzz(8, 1, max=%d) = %d, exceeds max", maxIvl, got)
 		}
 		if got < 1 {
-			t.Errorf("applyFuzz(8, max=%d) = %d, below 1", maxIvl, got)
+			t.Errorf("applyFuzz(8, 1, max=%d) = %d, below 1", maxIvl, got)
 		}
 	}
 }
+
+// --- cardFuzzFactor ---
+
+func TestCardFuzzFactorDeterministic(t *testing.T) {
+	c := Card{CardID: 42, LastReview: ptrT(t0)}
+	if a, b := cardFuzzFactor(c), cardFuzzFactor(c.clone()); a != b {
+		t.Errorf("cardFuzzFactor not deterministic: %v != %v", a, b)
+	}
+}
+
+func TestCardFuzzFactorRange(t *testing.T) {
+	seen := make(map[float64]bool)
+	for id := int64(0); id < 500; id++ {
+		c := Card{CardID: id, LastReview: ptrT(t0.Add(time.Duration(id) * time.Hour))}
+		u := cardFuzzFactor(c)
+		if u < 0 || u >= 1 {
+			t.Fatalf("cardFuzzFactor(card %d) = %v, want [0, 1)", id, u)
+		}
+		seen[u] = true
+	}
+	if len(seen) < 500 {
+		t.Errorf("cardFuzzFactor produced %d distinct values for 500 cards", len(seen))
+	}
+}
+
+func TestCardFuzzFactorDependsOnLastReview(t *testing.T) {
+	a := cardFuzzFactor(Card{CardID: 1})
+	b := cardFuzzFactor(Card{CardID: 1, LastReview: ptrT(t0)})
+	if a == b {
+		t.Error("cardFuzzFactor should change with LastReview")
+	}
+}
+
+func TestCardFuzzFactorDependsOnKey(t *testing.T) {
+	a := cardFuzzFactor(Card{Key: "a", LastReview: ptrT(t0)})
+	b := cardFuzzFactor(Card{Key: "b", LastReview: ptrT(t0)})
+	if a == b || a == cardFuzzFactor(Card{LastReview: ptrT(t0)}) {
+		t.Error("cardFuzzFactor should change with Key")
+	}
+}
+
+// --- FuzzMode ---
+
+func TestFuzzModeString(t *testing.T) {
+	tests := []struct {
+		m    FuzzMode
+		want string
+	}{
+		{FuzzRandom, "Random"},
+		{FuzzCardSeeded, "CardSeeded"},
+		{FuzzMode(-1), "FuzzMode(-1)"},
+		{FuzzMode(2), "FuzzMode(2)"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("FuzzMode(%d).String() = %q, want %q", int(tt.m), got, tt.want)
+		}
+	}
+}
+
+func TestFuzzModeJSONRoundTrip(t *testing.T) {
+	for _, m := range []FuzzMode{FuzzRandom, FuzzCardSeeded} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", m, err)
+		}
+		if string(data) != `"`+m.String()+`"` {
+			t.Errorf("Marshal(%v) = %s", m, data)
+		}
+		var got FuzzMode
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != m {
+			t.Errorf("round-trip: got %v, want %v", got, m)
+		}
+	}
+}
+
+func TestFuzzModeMarshalJSONInvalid(t *testing.T) {
+	if _, err := json.Marshal(FuzzMode(5)); err == nil {
+		t.Error("json.Marshal(FuzzMode(5)) should return error")
+	}
+}
+
+func TestFuzzModeUnmarshalJSONInvalid(t *testing.T) {
+	for _, input := range []string{`"Unknown"`, `""`, `1`} {
+		var m FuzzMode
+		if err := json.Unmarshal([]byte(input), &m); err == nil {
+			t.Errorf("json.Unmarshal(%s) should return error", input)
+		}
+	}
+}
+
+func TestStreamFuzzFactorReproducible(t *testing.T) {
+	for n := uint64(1); n <= 100; n++ {
+		a, b := streamFuzzFactor(7, n), streamFuzzFactor(7, n)
+		if a != b || a < 0 || a >= 1 {
+			t.Fatalf("streamFuzzFactor(7, %d) = %v, %v", n, a, b)
+		}
+	}
+	if streamFuzzFactor(7, 1) == streamFuzzFactor(8, 1) {
+		t.Error("different seeds should yield different streams")
+	}
+}
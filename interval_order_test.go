@@ -0,0 +1,119 @@
+package flux
+
+import (
+	"testing"
+	"time"
+)
+
+// previewDays returns the Hard, Good and Easy intervals in days from a preview.
+func previewDays(s *Scheduler, card Card, now time.Time) (hard, good, easy int) {
+	p := s.PreviewCard(card, now)
+	days := func(r Rating) int { return int(p[r].Due.Sub(now).Hours() / 24) }
+	return days(Hard), days(Good), days(Easy)
+}
+
+func TestPreviewCardIntervalOrder(t *testing.T) {
+	for _, cfg := range []SchedulerConfig{
+		noFuzzCfg(),
+		{Seed: 5},
+		{Seed: 5, FuzzMode: FuzzCardSeeded},
+		{Seed: 5, WeekdayWeights: weekdaysOff(time.Saturday, time.Sunday)},
+	} {
+		s := mustScheduler(t, cfg)
+		for _, stab := range []float64{0.1, 0.5, 1, 2, 5, 20, 100} {
+			for _, diff := range []float64{1, 5, 10} {
+				for _, elapsed := range []int{0, 1, 3, 30} {
+					card := reviewCard(t)
+					card.Stability, card.Difficulty = ptrF(stab), ptrF(diff)
+					now := t0.Add(time.Duration(elapsed) * 24 * time.Hour)
+					hard, good, easy := previewDays(s, card, now)
+					if hard < 1 || hard >= good || good >= easy {
+						t.Errorf("cfg %+v S=%v D=%v t=%d: Hard %d, Good %d, Easy %d not strictly increasing",
+							cfg, stab, diff, elapsed, hard, good, easy)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestReviewCardIntervalOrderRaisesGoodAndEasy(t *testing.T) {
+	// A same-day review of a card with tiny stability gives every rating a
+	// one-day interval; Good and Easy are pushed past Hard.
+	s := mustScheduler(t, noFuzzCfg())
+	card := reviewCard(t)
+	card.Stability, card.Difficulty = ptrF(0.1), ptrF(10)
+	for r, want := range map[Rating]int{Hard: 1, Good: 2, Easy: 3} {
+		c, _ := s.ReviewCard(card, r, t0)
+		if got := int(c.Due.Sub(t0).Hours() / 24); got != want {
+			t.Errorf("%s interval = %d, want %d", r, got, want)
+		}
+	}
+}
+
+func TestReviewCardIntervalOrderMaximumInterval(t *testing.T) {
+	// At the maximum interval the order cannot be kept; nothing exceeds it.
+	s := mustScheduler(t, SchedulerConfig{DisableFuzzing: true, MaximumInterval: 2})
+	card := reviewCard(t)
+	card.Stability = ptrF(0.1)
+	hard, good, easy := previewDays(s, card, t0)
+	if hard != 1 || good != 2 || easy != 2 {
+		t.Errorf("intervals = %d, %d, %d, want 1, 2, 2", hard, good, easy)
+	}
+}
+
+func TestPreviewCardDrawsOnce(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{Seed: 9})
+	s.PreviewCard(reviewCard(t), t0.Add(10*24*time.Hour))
+	if got := s.draws.Load(); got != 1 {
+		t.Errorf("draws after preview = %d, want 1", got)
+	}
+}
+
+// --- bounded fuzz ranges ---
+
+func TestFuzzRangeMinimum(t *testing.T) {
+	tests := []struct {
+		interval, minIvl, maxIvl int
+		lo, hi                   int
+	}{
+		{10, 1, 36500, 8, 12},
+		{10, 11, 36500, 11, 13}, // interval raised to the minimum first
+		{10, 20, 36500, 20, 23},
+		{10, 50, 48, 48, 48}, // minimum capped at the maximum
+	}
+	for _, tt := range tests {
+		lo, hi := fuzzRange(tt.interval, tt.minIvl, tt.maxIvl)
+		if lo != tt.lo || hi != tt.hi {
+			t.Errorf("fuzzRange(%d, %d, %d) = [%d, %d], want [%d, %d]",
+				tt.interval, tt.minIvl, tt.maxIvl, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
+
+func TestBoundedFuzzSmallInterval(t *testing.T) {
+	// A short interval raised to a minimum below 2.5 is returned unfuzzed.
+	flat := func(int) float64 { return 1 }
+	if got := applyFuzz(1, 2, 36500, 0.9); got != 2 {
+		t.Errorf("applyFuzz(1, min 2) = %d, want 2", got)
+	}
+	if got := weightedFuzz(1, 2, 36500, 0.9, flat); got != 2 {
+		t.Errorf("weightedFuzz(1, min 2) = %d, want 2", got)
+	}
+	if got := easiestDay(1, 2, 36500, flat); got != 2 {
+		t.Errorf("easiestDay(1, min 2) = %d, want 2", got)
+	}
+}
+
+func TestBoundedFuzzStaysInBounds(t *testing.T) {
+	flat := func(int) float64 { return 1 }
+	for i := 0; i < 100; i++ {
+		u := float64(i) / 100
+		if got := weightedFuzz(10, 11, 36500, u, flat); got < 11 || got > 13 {
+			t.Fatalf("weightedFuzz(10, min 11, u=%v) = %d, want [11, 13]", u, got)
+		}
+		if got := applyFuzz(10, 11, 36500, u); got < 11 || got > 14 {
+			t.Fatalf("applyFuzz(10, min 11, u=%v) = %d, want [11, 14]", u, got)
+		}
+	}
+}
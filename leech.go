@@ -0,0 +1,116 @@
+package flux
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+)
+
+// LeechAction selects what happens to a card that becomes a leech.
+type LeechAction int
+
+const (
+	LeechTag     LeechAction = iota // Flag the card; keep scheduling it.
+	LeechSuspend                    // Flag the card and suspend it.
+)
+
+var (
+	leechActionNames  = [...]string{LeechTag: "Tag", LeechSuspend: "Suspend"}
+	leechActionByName = map[string]LeechAction{
+		"Tag":     LeechTag,
+		"Suspend": LeechSuspend,
+	}
+)
+
+// Compile-time interface checks.
+var (
+	_ fmt.Stringer             = LeechAction(0)
+	_ json.Marshaler           = LeechAction(0)
+	_ json.Unmarshaler         = (*LeechAction)(nil)
+	_ encoding.TextMarshaler   = LeechAction(0)
+	_ encoding.TextUnmarshaler = (*LeechAction)(nil)
+)
+
+func (a LeechAction) isValid() bool {
+	return a >= LeechTag && a <= LeechSuspend
+}
+
+// String returns the name of the leech action ("Tag", "Suspend").
+// For invalid values it returns "LeechAction(n)".
+func (a LeechAction) String() string {
+	if a.isValid() {
+		return leechActionNames[a]
+	}
+	return fmt.Sprintf("LeechAction(%d)", int(a))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a LeechAction) MarshalText() ([]byte, error) {
+	if !a.isValid() {
+		return nil, fmt.Errorf("flux: invalid leech action: %d", int(a))
+	}
+	return []byte(leechActionNames[a]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *LeechAction) UnmarshalText(text []byte) error {
+	v, ok := leechActionByName[string(text)]
+	if !ok {
+		return fmt.Errorf("flux: invalid leech action: %q", text)
+	}
+	*a = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. LeechAction serializes as a JSON string.
+func (a LeechAction) MarshalJSON() ([]byte, error) {
+	text, err := a.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Expects a JSON string.
+func (a *LeechAction) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("flux: invalid leech action: %s", data)
+	}
+	return a.UnmarshalText([]byte(str))
+}
+
+// LeechPolicy configures leech detection. As in Anki, a card becomes a leech
+// when its lapse count reaches Threshold, and is reported again every
+// WarningInterval lapses after that.
+type LeechPolicy struct {
+	Threshold       int         `json:"threshold"`        // zero → leech detection off
+	WarningInterval int         `json:"warning_interval"` // zero → max(Threshold/2, 1)
+	Action          LeechAction `json:"action"`           // zero → LeechTag
+}
+
+// withDefaults validates the policy and fills in its zero-value fields.
+func (p LeechPolicy) withDefaults() (LeechPolicy, error) {
+	if p.Threshold < 0 {
+		return p, fmt.Errorf("flux: leech threshold %d must not be negative", p.Threshold)
+	}
+	if p.WarningInterval < 0 {
+		return p, fmt.Errorf("flux: leech warning interval %d must not be negative", p.WarningInterval)
+	}
+	if p.WarningInterval == 0 {
+		p.WarningInterval = max(p.Threshold/2, 1)
+	}
+	if !p.Action.isValid() {
+		return p, fmt.Errorf("flux: invalid leech action: %d", int(p.Action))
+	}
+	return p, nil
+}
+
+// isLeech reports whether a card that has just lapsed for the lapses-th
+// time should be reported as a leech.
+func (p LeechPolicy) isLeech(lapses int) bool {
+	if p.Threshold == 0 || lapses < p.Threshold {
+		return false
+	}
+	return (lapses-p.Threshold)%p.WarningInterval == 0
+}
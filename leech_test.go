@@ -0,0 +1,192 @@
+package flux
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- LeechAction ---
+
+func TestLeechActionString(t *testing.T) {
+	tests := []struct {
+		a    LeechAction
+		want string
+	}{
+		{LeechTag, "Tag"},
+		{LeechSuspend, "Suspend"},
+		{LeechAction(-1), "LeechAction(-1)"},
+		{LeechAction(2), "LeechAction(2)"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("LeechAction(%d).String() = %q, want %q", int(tt.a), got, tt.want)
+		}
+	}
+}
+
+func TestLeechActionJSONRoundTrip(t *testing.T) {
+	for _, a := range []LeechAction{LeechTag, LeechSuspend} {
+		data, err := json.Marshal(a)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", a, err)
+		}
+		if string(data) != `"`+a.String()+`"` {
+			t.Errorf("Marshal(%v) = %s", a, data)
+		}
+		var got LeechAction
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != a {
+			t.Errorf("round-trip: got %v, want %v", got, a)
+		}
+	}
+}
+
+func TestLeechActionMarshalJSONInvalid(t *testing.T) {
+	if _, err := json.Marshal(LeechAction(2)); err == nil {
+		t.Error("json.Marshal(LeechAction(2)) should return error")
+	}
+}
+
+func TestLeechActionUnmarshalJSONInvalid(t *testing.T) {
+	for _, input := range []string{`"Unknown"`, `""`, `1`} {
+		var a LeechAction
+		if err := json.Unmarshal([]byte(input), &a); err == nil {
+			t.Errorf("json.Unmarshal(%s) should return error", input)
+		}
+	}
+}
+
+func TestLeechActionZeroIsTag(t *testing.T) {
+	var a LeechAction
+	if a != LeechTag {
+		t.Errorf("zero LeechAction = %v, want Tag", a)
+	}
+}
+
+// --- LeechPolicy ---
+
+func TestLeechPolicyDefaults(t *testing.T) {
+	tests := []struct {
+		in, want LeechPolicy
+	}{
+		{LeechPolicy{}, LeechPolicy{WarningInterval: 1, Action: LeechTag}},
+		{LeechPolicy{Threshold: 8}, LeechPolicy{Threshold: 8, WarningInterval: 4, Action: LeechTag}},
+		{LeechPolicy{Threshold: 3, WarningInterval: 5, Action: LeechSuspend}, LeechPolicy{Threshold: 3, WarningInterval: 5, Action: LeechSuspend}},
+	}
+	for _, tt := range tests {
+		got, err := tt.in.withDefaults()
+		if err != nil || got != tt.want {
+			t.Errorf("withDefaults(%+v) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestNewSchedulerInvalidLeechPolicy(t *testing.T) {
+	tests := []struct {
+		p    LeechPolicy
+		want string
+	}{
+		{LeechPolicy{Threshold: -1}, "threshold"},
+		{LeechPolicy{Threshold: 8, WarningInterval: -1}, "warning interval"},
+		{LeechPolicy{Threshold: 8, Action: LeechAction(9)}, "leech action"},
+	}
+	for _, tt := range tests {
+		_, err := NewScheduler(SchedulerConfig{Leech: tt.p})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Leech %+v: err = %v, want %q error", tt.p, err, tt.want)
+		}
+	}
+}
+
+func TestLeechPolicyIsLeech(t *testing.T) {
+	p, _ := LeechPolicy{Threshold: 8}.withDefaults() // warnings every 4 lapses
+	var got []int
+	for lapses := 1; lapses <= 20; lapses++ {
+		if p.isLeech(lapses) {
+			got = append(got, lapses)
+		}
+	}
+	if want := []int{8, 12, 16, 20}; !slices.Equal(got, want) {
+		t.Errorf("leech at lapses %v, want %v", got, want)
+	}
+
+	off, _ := LeechPolicy{}.withDefaults()
+	if off.isLeech(100) {
+		t.Error("zero threshold should disable leech detection")
+	}
+}
+
+// --- Scheduler ---
+
+// lapse reviews a Review-state card with Again, then relearns it with Good.
+func lapse(s *Scheduler, c Card, now time.Time) (Card, ReviewLog) {
+	c, log := s.ReviewCard(c, Again, now)
+	c, _ = s.ReviewCard(c, Good, now.Add(10*time.Minute))
+	return c, log
+}
+
+func TestReviewCardLeechTag(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{DisableFuzzing: true, Leech: LeechPolicy{Threshold: 2}})
+	c := reviewCard(t)
+	now := t0.Add(5 * 24 * time.Hour)
+
+	c, log := lapse(s, c, now)
+	if c.Leech || log.Leech {
+		t.Fatal("first lapse should not make a leech")
+	}
+	c, log = lapse(s, c, now.Add(5*24*time.Hour))
+	if !c.Leech || !log.Leech {
+		t.Errorf("second lapse: Card.Leech=%v, ReviewLog.Leech=%v, want true", c.Leech, log.Leech)
+	}
+	if c.Queue != QueueActive {
+		t.Error("LeechTag should not suspend the card")
+	}
+
+	// The flag sticks; the log only reports threshold and warning lapses.
+	c, _ = s.ReviewCard(c, Good, now.Add(20*24*time.Hour))
+	if !c.Leech {
+		t.Error("Card.Leech cleared by a later review")
+	}
+}
+
+func TestReviewCardLeechSuspend(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{
+		DisableFuzzing: true,
+		Leech:          LeechPolicy{Threshold: 1, Action: LeechSuspend},
+	})
+	c, log := s.ReviewCard(reviewCard(t), Again, t0.Add(5*24*time.Hour))
+	if !c.Leech || c.Queue != QueueSuspended || !log.Leech {
+		t.Errorf("Leech=%v Queue=%v log.Leech=%v, want true, Suspended, true", c.Leech, c.Queue, log.Leech)
+	}
+}
+
+func TestReviewCardLeechDisabled(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := reviewCard(t)
+	c.Lapses = 50
+	c, log := s.ReviewCard(c, Again, t0.Add(5*24*time.Hour))
+	if c.Leech || log.Leech {
+		t.Error("leech detection should be off by default")
+	}
+}
+
+func TestSchedulerJSONLeechPolicy(t *testing.T) {
+	want := LeechPolicy{Threshold: 6, WarningInterval: 2, Action: LeechSuspend}
+	s := mustScheduler(t, SchedulerConfig{Leech: want})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var s2 Scheduler
+	if err := json.Unmarshal(data, &s2); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s2.leech != want {
+		t.Errorf("round-trip leech policy = %+v, want %+v", s2.leech, want)
+	}
+}
@@ -0,0 +1,40 @@
+package flux
+
+import (
+	"math"
+	"time"
+)
+
+// LoadBalancer reports the existing review workload so the Scheduler can
+// spread fuzzed due dates evenly across days, like the FSRS Helper and Anki
+// load balancers. Implementations are typically backed by a count query over
+// the caller's card store and must be safe for concurrent use if the
+// Scheduler is shared.
+type LoadBalancer interface {
+	// DueCount returns how many cards are already due on the day of due,
+	// the candidate due date for the card being scheduled.
+	DueCount(due time.Time) int
+}
+
+// LoadBalancerFunc adapts an ordinary function to the LoadBalancer interface.
+type LoadBalancerFunc func(due time.Time) int
+
+// DueCount calls f(due).
+func (f LoadBalancerFunc) DueCount(due time.Time) int {
+	return f(due)
+}
+
+// loadWeightExponent controls how strongly busy days are avoided.
+const loadWeightExponent = 2.15
+
+// loadWeight returns the relative preference for scheduling a card d days
+// out, on due:
+//
+//	w(d) = (dueCount(due) + 1)^(-2.15) / d
+//
+// Empty days dominate and, all else equal, shorter intervals are slightly
+// favored.
+func loadWeight(lb LoadBalancer, d int, due time.Time) float64 {
+	count := max(lb.DueCount(due), 0)
+	return math.Pow(float64(count+1), -loadWeightExponent) / float64(d)
+}
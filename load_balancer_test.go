@@ -0,0 +1,127 @@
+package flux
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// busyExcept returns a LoadBalancer reporting busy days everywhere except
+// on the day free days after now.
+func busyExcept(now time.Time, free int) LoadBalancer {
+	return LoadBalancerFunc(func(due time.Time) int {
+		if due.Equal(now.Add(time.Duration(free) * 24 * time.Hour)) {
+			return 0
+		}
+		return 100
+	})
+}
+
+// byLoad returns a weightedFuzz weight function using only lb's load
+// weights for days counted from t0.
+func byLoad(lb LoadBalancer) func(int) float64 {
+	return func(d int) float64 {
+		return loadWeight(lb, d, t0.Add(time.Duration(d)*24*time.Hour))
+	}
+}
+
+func TestLoadBalancerFunc(t *testing.T) {
+	var lb LoadBalancer = LoadBalancerFunc(func(time.Time) int { return 7 })
+	if got := lb.DueCount(t0); got != 7 {
+		t.Errorf("DueCount = %d, want 7", got)
+	}
+}
+
+func TestWeightedFuzzLoadSmallInterval(t *testing.T) {
+	lb := busyExcept(t0, 5)
+	if got := weightedFuzz(2, 1, 36500, 0.5, byLoad(lb)); got != 2 {
+		t.Errorf("weightedFuzz(2) = %d, want 2", got)
+	}
+}
+
+func TestWeightedFuzzLoadPrefersEmptyDay(t *testing.T) {
+	// interval=10 → fuzz range [8, 12]; only day 11 is empty.
+	lb := busyExcept(t0, 11)
+	hits := 0
+	for i := 0; i < 100; i++ {
+		u := (float64(i) + 0.5) / 100
+		got := weightedFuzz(10, 1, 36500, u, byLoad(lb))
+		if got < 8 || got > 12 {
+			t.Fatalf("weightedFuzz(10, 1, u=%v) = %d, want [8, 12]", u, got)
+		}
+		if got == 11 {
+			hits++
+		}
+	}
+	if hits < 95 {
+		t.Errorf("empty day chosen %d/100 times, want >= 95", hits)
+	}
+}
+
+func TestWeightedFuzzLoadUniformLoadFavorsShorter(t *testing.T) {
+	lb := LoadBalancerFunc(func(time.Time) int { return 3 })
+	// Equal load: weights ∝ 1/d, so u near 0 picks lo and u near 1 picks hi.
+	if got := weightedFuzz(10, 1, 36500, 0, byLoad(lb)); got != 8 {
+		t.Errorf("weightedFuzz(u=0) = %d, want 8", got)
+	}
+	if got := weightedFuzz(10, 1, 36500, 0.999999, byLoad(lb)); got != 12 {
+		t.Errorf("weightedFuzz(u≈1) = %d, want 12", got)
+	}
+}
+
+func TestWeightedFuzzLoadRoundingFallback(t *testing.T) {
+	// u=1 is outside [0, 1); the last candidate is returned.
+	lb := LoadBalancerFunc(func(time.Time) int { return 0 })
+	if got := weightedFuzz(10, 1, 36500, 1, byLoad(lb)); got != 12 {
+		t.Errorf("weightedFuzz(u=1) = %d, want 12", got)
+	}
+}
+
+func TestWeightedFuzzLoadNegativeCount(t *testing.T) {
+	lb := LoadBalancerFunc(func(time.Time) int { return -5 })
+	got := weightedFuzz(10, 1, 36500, 0.5, byLoad(lb))
+	if got < 8 || got > 12 {
+		t.Errorf("weightedFuzz with negative counts = %d, want [8, 12]", got)
+	}
+}
+
+func TestWeightedFuzzLoadMaxIvl(t *testing.T) {
+	lb := LoadBalancerFunc(func(time.Time) int { return 0 })
+	for i := 0; i < 20; i++ {
+		if got := weightedFuzz(50, 1, 48, float64(i)/20, byLoad(lb)); got > 48 {
+			t.Errorf("weightedFuzz(50, 1, maxIvl=48) = %d, exceeds max", got)
+		}
+	}
+}
+
+func TestSchedulerLoadBalancer(t *testing.T) {
+	t1 := t0.Add(10 * 24 * time.Hour)
+	s := mustScheduler(t, SchedulerConfig{Seed: 3, DisableFuzzing: true})
+	plain, _ := s.ReviewCard(reviewCard(t), Good, t1)
+	ivl := int(plain.Due.Sub(t1).Hours() / 24)
+	lo, hi := fuzzRange(ivl, 1, 36500)
+
+	free := hi
+	s = mustScheduler(t, SchedulerConfig{Seed: 3, LoadBalancer: busyExcept(t1, free)})
+	for i := 0; i < 20; i++ {
+		c, _ := s.ReviewCard(reviewCard(t), Good, t1)
+		if got := int(c.Due.Sub(t1).Hours() / 24); got != free {
+			t.Errorf("review %d: interval %d, want empty day %d (range [%d, %d])", i, got, free, lo, hi)
+		}
+	}
+}
+
+func TestSchedulerJSONKeepsLoadBalancer(t *testing.T) {
+	lb := LoadBalancerFunc(func(time.Time) int { return 0 })
+	s := mustScheduler(t, SchedulerConfig{LoadBalancer: lb})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s.loadBalancer == nil {
+		t.Error("Unmarshal dropped the receiver's LoadBalancer")
+	}
+}
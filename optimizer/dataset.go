@@ -1,7 +1,9 @@
 package optimizer
 
 import (
+	"cmp"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/sky-flux/flux"
@@ -15,26 +17,37 @@ type review struct {
 	reviewTime  time.Time // original review timestamp (for Scheduler replay)
 }
 
-// formatRevlogs groups review logs by card ID and sorts each group by time.
+// cardKey identifies a card in the training data by its CardID and Key, so
+// cards keyed by strings alone are told apart.
+type cardKey struct {
+	id  int64
+	key string
+}
+
+// keyOf returns the cardKey of the card log records.
+func keyOf(log flux.ReviewLog) cardKey {
+	return cardKey{id: log.CardID, key: log.CardKey}
+}
+
+// compareCardKeys orders cardKeys by id, then key.
+func compareCardKeys(a, b cardKey) int {
+	if c := cmp.Compare(a.id, b.id); c != 0 {
+		return c
+	}
+	return strings.Compare(a.key, b.key)
+}
+
+// formatRevlogs groups review logs by card and sorts each group by time,
+// keeping only trainable reviews after the card's last reset.
 // Each review computes elapsed_days from the previous review and a binary label.
-func formatRevlogs(logs []flux.ReviewLog) map[int64][]review {
+func formatRevlogs(logs []flux.ReviewLog) map[cardKey][]review {
 	if len(logs) == 0 {
 		return nil
 	}
 
-	// Group by card ID.
-	groups := make(map[int64][]flux.ReviewLog)
-	for _, log := range logs {
-		groups[log.CardID] = append(groups[log.CardID], log)
-	}
-
-	result := make(map[int64][]review, len(groups))
+	groups := groupRatedLogs(logs)
+	result := make(map[cardKey][]review, len(groups))
 	for cardID, cardLogs := range groups {
-		// Sort by review time.
-		sort.Slice(cardLogs, func(i, j int) bool {
-			return cardLogs[i].ReviewDatetime.Before(cardLogs[j].ReviewDatetime)
-		})
-
 		reviews := make([]review, len(cardLogs))
 		for i, log := range cardLogs {
 			var elapsed float64
@@ -62,7 +75,7 @@ func formatRevlogs(logs []flux.ReviewLog) map[int64][]review {
 
 // countCrossDayReviews counts reviews where elapsed_days >= 1 (cross-day reviews).
 // The first review of each card is never cross-day (elapsed_days = 0).
-func countCrossDayReviews(data map[int64][]review) int {
+func countCrossDayReviews(data map[cardKey][]review) int {
 	count := 0
 	for _, reviews := range data {
 		for _, r := range reviews {
@@ -73,3 +86,52 @@ func countCrossDayReviews(data map[int64][]review) int {
 	}
 	return count
 }
+
+// groupRatedLogs groups review logs by card and sorts each group by time.
+// A KindReset log starts the card's history afresh, so only the trainable
+// reviews after the last reset are kept; cards left empty are dropped.
+func groupRatedLogs(logs []flux.ReviewLog) map[cardKey][]flux.ReviewLog {
+	groups := make(map[cardKey][]flux.ReviewLog)
+	for _, log := range logs {
+		groups[keyOf(log)] = append(groups[keyOf(log)], log)
+	}
+
+	for cardID, cardLogs := range groups {
+		// Sort by review time; a reset and a review at the same instant
+		// keep their input order.
+		sort.SliceStable(cardLogs, func(i, j int) bool {
+			return cardLogs[i].ReviewDatetime.Before(cardLogs[j].ReviewDatetime)
+		})
+
+		start := 0
+		for i, log := range cardLogs {
+			if log.Kind == flux.KindReset {
+				start = i + 1
+			}
+		}
+		var rated []flux.ReviewLog
+		for _, log := range cardLogs[start:] {
+			if trainable(log.Kind) {
+				rated = append(rated, log)
+			}
+		}
+		if len(rated) == 0 {
+			delete(groups, cardID)
+			continue
+		}
+		groups[cardID] = rated
+	}
+	return groups
+}
+
+// trainable reports whether logs of kind k are scheduled reviews the model
+// should learn from. Filtered (cram) reviews, manual reschedules and resets
+// are excluded.
+func trainable(k flux.ReviewKind) bool {
+	switch k {
+	case flux.KindRated, flux.KindLearn, flux.KindReview, flux.KindRelearn:
+		return true
+	default:
+		return false
+	}
+}
@@ -27,7 +27,7 @@ func TestFormatRevlogsSingleCard(t *testing.T) {
 	if len(got) != 1 {
 		t.Fatalf("got %d groups, want 1", len(got))
 	}
-	reviews := got[1]
+	reviews := got[cardKey{id: 1}]
 	if len(reviews) != 3 {
 		t.Fatalf("card 1 has %d reviews, want 3", len(reviews))
 	}
@@ -54,11 +54,11 @@ func TestFormatRevlogsMultiCard(t *testing.T) {
 	if len(got) != 2 {
 		t.Fatalf("got %d groups, want 2", len(got))
 	}
-	if len(got[1]) != 1 {
-		t.Errorf("card 1 has %d reviews, want 1", len(got[1]))
+	if len(got[cardKey{id: 1}]) != 1 {
+		t.Errorf("card 1 has %d reviews, want 1", len(got[cardKey{id: 1}]))
 	}
-	if len(got[2]) != 2 {
-		t.Errorf("card 2 has %d reviews, want 2", len(got[2]))
+	if len(got[cardKey{id: 2}]) != 2 {
+		t.Errorf("card 2 has %d reviews, want 2", len(got[cardKey{id: 2}]))
 	}
 }
 
@@ -69,7 +69,7 @@ func TestFormatRevlogsElapsedDays(t *testing.T) {
 		{CardID: 1, Rating: flux.Again, ReviewDatetime: t0.Add(3*24*time.Hour + time.Hour)},
 	}
 	got := formatRevlogs(logs)
-	reviews := got[1]
+	reviews := got[cardKey{id: 1}]
 
 	// First review: elapsed_days = 0 (no previous).
 	if reviews[0].elapsedDays != 0 {
@@ -88,7 +88,7 @@ func TestFormatRevlogsLabel(t *testing.T) {
 		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(48 * time.Hour)},
 	}
 	got := formatRevlogs(logs)
-	reviews := got[1]
+	reviews := got[cardKey{id: 1}]
 
 	// Again → label=0, Hard/Good/Easy → label=1.
 	if reviews[0].label != 0 {
@@ -139,3 +139,85 @@ func assertFloatOpt(t *testing.T, name string, got, want float64) {
 		t.Errorf("%s = %.6f, want %.6f (diff %.6f)", name, got, want, diff)
 	}
 }
+
+func TestFormatRevlogsAfterReset(t *testing.T) {
+	logs := []flux.ReviewLog{
+		{CardID: 1, Rating: flux.Again, ReviewDatetime: t0},
+		{CardID: 1, Rating: flux.Again, ReviewDatetime: t0.Add(2 * 24 * time.Hour)},
+		{CardID: 1, Kind: flux.KindReset, ReviewDatetime: t0.Add(3 * 24 * time.Hour)},
+		{CardID: 1, Rating: flux.Easy, ReviewDatetime: t0.Add(3 * 24 * time.Hour)},
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(10 * 24 * time.Hour)},
+		{CardID: 2, Rating: flux.Good, ReviewDatetime: t0},
+		{CardID: 2, Kind: flux.KindReset, ReviewDatetime: t0.Add(time.Hour)},
+	}
+	got := formatRevlogs(logs)
+
+	if _, ok := got[cardKey{id: 2}]; ok {
+		t.Error("card reset after its only review should be dropped")
+	}
+	reviews := got[cardKey{id: 1}]
+	if len(reviews) != 2 {
+		t.Fatalf("card 1 has %d reviews, want 2 after the reset", len(reviews))
+	}
+	if reviews[0].rating != flux.Easy || reviews[0].elapsedDays != 0 {
+		t.Errorf("first review after reset = %+v, want Easy with 0 elapsed days", reviews[0])
+	}
+	assertFloatOpt(t, "elapsed after reset", reviews[1].elapsedDays, 7)
+}
+
+func TestFormatRevlogsSkipsManual(t *testing.T) {
+	due := t0.Add(30 * 24 * time.Hour)
+	logs := []flux.ReviewLog{
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0},
+		{CardID: 1, Kind: flux.KindManual, ReviewDatetime: t0.Add(24 * time.Hour), Due: &due},
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(5 * 24 * time.Hour)},
+	}
+	reviews := formatRevlogs(logs)[cardKey{id: 1}]
+	if len(reviews) != 2 {
+		t.Fatalf("card 1 has %d reviews, want 2 without the manual entry", len(reviews))
+	}
+	assertFloatOpt(t, "elapsed across manual entry", reviews[1].elapsedDays, 5)
+}
+
+func TestFormatRevlogsReviewKinds(t *testing.T) {
+	logs := []flux.ReviewLog{
+		{CardID: 1, Kind: flux.KindLearn, Rating: flux.Good, ReviewDatetime: t0},
+		{CardID: 1, Kind: flux.KindFiltered, Rating: flux.Again, ReviewDatetime: t0.Add(24 * time.Hour)},
+		{CardID: 1, Kind: flux.KindReview, Rating: flux.Again, ReviewDatetime: t0.Add(3 * 24 * time.Hour)},
+		{CardID: 1, Kind: flux.KindRelearn, Rating: flux.Good, ReviewDatetime: t0.Add(3*24*time.Hour + time.Hour)},
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(6 * 24 * time.Hour)}, // legacy, no kind
+	}
+	reviews := formatRevlogs(logs)[cardKey{id: 1}]
+	if len(reviews) != 4 {
+		t.Fatalf("card 1 has %d reviews, want 4 without the filtered one", len(reviews))
+	}
+	assertFloatOpt(t, "elapsed across filtered review", reviews[1].elapsedDays, 3)
+}
+
+func TestFormatRevlogsGroupsByKey(t *testing.T) {
+	logs := []flux.ReviewLog{
+		{CardKey: "3f0c", Rating: flux.Good, ReviewDatetime: t0},
+		{CardKey: "9a1e", Rating: flux.Again, ReviewDatetime: t0},
+		{CardKey: "3f0c", Rating: flux.Good, ReviewDatetime: t0.Add(4 * 24 * time.Hour)},
+	}
+	got := formatRevlogs(logs)
+	if len(got) != 2 || len(got[cardKey{key: "3f0c"}]) != 2 || len(got[cardKey{key: "9a1e"}]) != 1 {
+		t.Errorf("formatRevlogs grouped string keys as %v", got)
+	}
+}
+
+func TestTrainable(t *testing.T) {
+	for k, want := range map[flux.ReviewKind]bool{
+		flux.KindRated:    true,
+		flux.KindLearn:    true,
+		flux.KindReview:   true,
+		flux.KindRelearn:  true,
+		flux.KindFiltered: false,
+		flux.KindManual:   false,
+		flux.KindReset:    false,
+	} {
+		if got := trainable(k); got != want {
+			t.Errorf("trainable(%v) = %v, want %v", k, got, want)
+		}
+	}
+}
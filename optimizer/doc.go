@@ -2,13 +2,22 @@
 //
 // It provides two main capabilities:
 //
-//   - [Optimizer.ComputeOptimalParameters] trains the 21 FSRS parameters
-//     using mini-batch gradient descent with the [Adam] optimizer and
-//     [CosineAnnealing] learning rate schedule. Gradients are computed via
-//     numerical central differences on binary cross-entropy loss.
+//   - [Optimizer.ComputeOptimalParameters] trains the 21 FSRS parameters.
+//     It first pretrains the initial stabilities w[0..3] from the recall
+//     rate at each card's first cross-day review (see
+//     [Optimizer.PretrainParameters], which also works alone on small
+//     datasets), then uses mini-batch gradient descent with the [Adam]
+//     optimizer and [CosineAnnealing] learning rate schedule. Gradients of
+//     the binary cross-entropy loss are computed exactly through the FSRS v6
+//     recurrences using forward-mode automatic differentiation.
 //
 //   - [Optimizer.ComputeOptimalRetention] finds the desired retention value
-//     that minimizes total review cost via Monte Carlo simulation.
+//     that minimizes total review cost via Monte Carlo simulation, searching
+//     a configurable range continuously; [Optimizer.ComputeRetentionCurve]
+//     also returns the cost at every evaluated retention. The search can
+//     instead maximize knowledge or minimize workload, subject to a daily
+//     time budget or a knowledge target; see [RetentionObjective] and
+//     [RetentionConstraints].
 //
 // # Usage
 //
@@ -19,6 +28,8 @@
 // # Data Requirements
 //
 // Parameter optimization requires enough cross-day reviews (at least
-// MiniBatchSize, default 512). Optimal retention additionally requires
-// ReviewDuration to be set on all review logs.
+// MiniBatchSize, default 512); pretraining needs only one card with a
+// cross-day review. Optimal retention additionally requires
+// ReviewDuration to be set on all rated review logs; manual reschedules,
+// resets and cram reviews need none.
 package optimizer
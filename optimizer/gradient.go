@@ -0,0 +1,260 @@
+package optimizer
+
+import (
+	"math"
+
+	"github.com/sky-flux/flux"
+)
+
+// dual is a forward-mode dual number: a value together with its partial
+// derivatives with respect to the 21 FSRS parameters.
+type dual struct {
+	v float64
+	d [21]float64
+}
+
+// constant returns a dual with zero derivatives.
+func constant(v float64) dual {
+	return dual{v: v}
+}
+
+// paramDuals seeds one dual per parameter with a unit derivative in its own slot.
+func paramDuals(params [21]float64) [21]dual {
+	var w [21]dual
+	for i := 0; i < 21; i++ {
+		w[i].v = params[i]
+		w[i].d[i] = 1
+	}
+	return w
+}
+
+func (a dual) add(b dual) dual {
+	out := dual{v: a.v + b.v}
+	for i := range out.d {
+		out.d[i] = a.d[i] + b.d[i]
+	}
+	return out
+}
+
+func (a dual) sub(b dual) dual {
+	out := dual{v: a.v - b.v}
+	for i := range out.d {
+		out.d[i] = a.d[i] - b.d[i]
+	}
+	return out
+}
+
+func (a dual) mul(b dual) dual {
+	out := dual{v: a.v * b.v}
+	for i := range out.d {
+		out.d[i] = a.d[i]*b.v + a.v*b.d[i]
+	}
+	return out
+}
+
+func (a dual) div(b dual) dual {
+	out := dual{v: a.v / b.v}
+	b2 := b.v * b.v
+	for i := range out.d {
+		out.d[i] = (a.d[i]*b.v - a.v*b.d[i]) / b2
+	}
+	return out
+}
+
+// scale returns k·a for a constant k.
+func (a dual) scale(k float64) dual {
+	out := dual{v: a.v * k}
+	for i := range out.d {
+		out.d[i] = a.d[i] * k
+	}
+	return out
+}
+
+// shift returns a + k for a constant k.
+func (a dual) shift(k float64) dual {
+	a.v += k
+	return a
+}
+
+func (a dual) exp() dual {
+	v := math.Exp(a.v)
+	out := a.scale(v)
+	out.v = v
+	return out
+}
+
+func (a dual) log() dual {
+	out := dual{v: math.Log(a.v)}
+	for i := range out.d {
+		out.d[i] = a.d[i] / a.v
+	}
+	return out
+}
+
+// pow returns a^b for a > 0, differentiating through both base and exponent.
+func (a dual) pow(b dual) dual {
+	return b.mul(a.log()).exp()
+}
+
+// analyticGradient computes the average BCE loss over all cross-day reviews
+// together with its exact gradient with respect to every parameter, using
+// forward-mode automatic differentiation through the FSRS v6 recurrences.
+// One replay per card replaces the 42 replays of numericalGradient. Cards
+// are spread across up to workers goroutines and reduced in card ID order.
+func analyticGradient(params [21]float64, data map[cardKey][]review, workers int) (float64, [21]float64) {
+	w := paramDuals(params)
+
+	cardIDs := sortedCardIDs(data)
+	losses := make([]dual, len(cardIDs))
+	counts := make([]int, len(cardIDs))
+	parallelFor(len(cardIDs), workers, func(i int) {
+		losses[i], counts[i] = seqLoss(&w, data[cardIDs[i]])
+	})
+
+	var total dual
+	var count int
+	for i := range cardIDs {
+		total = total.add(losses[i])
+		count += counts[i]
+	}
+
+	if count == 0 {
+		return 0, [21]float64{}
+	}
+	mean := total.scale(1 / float64(count))
+	return mean.v, mean.d
+}
+
+// seqLoss replays one card's review history and returns the summed BCE loss
+// of its cross-day reviews together with their count. It mirrors the
+// Scheduler replay in computeBatchLoss step for step.
+func seqLoss(w *[21]dual, reviews []review) (dual, int) {
+	var loss, s, d dual
+	var count int
+	for i, rev := range reviews {
+		if i == 0 {
+			// First review: initialize S and D.
+			s = initStabilityDual(w, rev.rating)
+			d = clampDDual(initDifficultyDual(w, rev.rating))
+			continue
+		}
+		if rev.elapsedDays < 1 {
+			// Same-day review.
+			s = shortTermStabilityDual(w, s, rev.rating)
+		} else {
+			// Cross-day review: predict, score, then update.
+			r := retrievabilityDual(w, rev.elapsedDays, s)
+			loss = loss.add(bceLossDual(r, rev.label))
+			count++
+			s = nextStabilityDual(w, d, s, r, rev.rating)
+		}
+		d = nextDifficultyDual(w, d, rev.rating)
+	}
+	return loss, count
+}
+
+// retrievabilityDual computes R(t, S) = (1 + FACTOR * t / S) ^ DECAY.
+func retrievabilityDual(w *[21]dual, elapsedDays float64, s dual) dual {
+	decay := w[20].scale(-1)
+	factor := constant(math.Log(0.9)).div(decay).exp().shift(-1)
+	return factor.scale(elapsedDays).div(s).shift(1).pow(decay)
+}
+
+// initStabilityDual returns S₀(G) = clamp_s(w[G-1]).
+func initStabilityDual(w *[21]dual, r flux.Rating) dual {
+	return clampSDual(w[r-1])
+}
+
+// initDifficultyDual returns the unclamped D₀(G) = w[4] - e^(w[5] * (G - 1)) + 1.
+func initDifficultyDual(w *[21]dual, r flux.Rating) dual {
+	return w[4].sub(w[5].scale(float64(r - 1)).exp()).shift(1)
+}
+
+// shortTermStabilityDual computes the same-day review stability.
+// SInc = e^(w[17] * (G - 3 + w[18])) * S^(-w[19]), floored at 1 for Good/Easy.
+func shortTermStabilityDual(w *[21]dual, s dual, r flux.Rating) dual {
+	sInc := w[17].mul(w[18].shift(float64(r) - 3)).exp().mul(s.pow(w[19].scale(-1)))
+	if (r == flux.Good || r == flux.Easy) && sInc.v < 1 {
+		sInc = constant(1)
+	}
+	return clampSDual(s.mul(sInc))
+}
+
+// nextDifficultyDual computes the updated difficulty with linear damping
+// and mean reversion toward the unclamped D₀(Easy).
+func nextDifficultyDual(w *[21]dual, d dual, r flux.Rating) dual {
+	deltaD := w[6].scale(-(float64(r) - 3))
+	dPrime := d.add(constant(10).sub(d).mul(deltaD).scale(1.0 / 9))
+	d0Easy := initDifficultyDual(w, flux.Easy)
+	return clampDDual(w[7].mul(d0Easy).add(constant(1).sub(w[7]).mul(dPrime)))
+}
+
+// nextStabilityDual dispatches to the recall or forget stability formula.
+func nextStabilityDual(w *[21]dual, d, s, r dual, rating flux.Rating) dual {
+	if rating == flux.Again {
+		return nextForgetStabilityDual(w, d, s, r)
+	}
+	return nextRecallStabilityDual(w, d, s, r, rating)
+}
+
+// nextRecallStabilityDual computes stability after a successful recall.
+// S'_r = S * (1 + e^w[8] * (11-D) * S^(-w[9]) * (e^((1-R)*w[10]) - 1) * hardPenalty * easyBonus)
+func nextRecallStabilityDual(w *[21]dual, d, s, r dual, rating flux.Rating) dual {
+	inc := w[8].exp().
+		mul(constant(11).sub(d)).
+		mul(s.pow(w[9].scale(-1))).
+		mul(constant(1).sub(r).mul(w[10]).exp().shift(-1))
+	if rating == flux.Hard {
+		inc = inc.mul(w[15])
+	}
+	if rating == flux.Easy {
+		inc = inc.mul(w[16])
+	}
+	return s.mul(inc.shift(1))
+}
+
+// nextForgetStabilityDual computes stability after forgetting: min(long, short).
+// long = w[11] * D^(-w[12]) * ((S+1)^w[13] - 1) * e^((1-R)*w[14])
+// short = S / e^(w[17] * w[18])
+func nextForgetStabilityDual(w *[21]dual, d, s, r dual) dual {
+	long := w[11].
+		mul(d.pow(w[12].scale(-1))).
+		mul(s.shift(1).pow(w[13]).shift(-1)).
+		mul(constant(1).sub(r).mul(w[14]).exp())
+	short := s.div(w[17].mul(w[18]).exp())
+	if short.v < long.v {
+		return short
+	}
+	return long
+}
+
+// bceLossDual computes -[y*ln(p) + (1-y)*ln(1-p)]. Predictions outside
+// [bceClamp, 1-bceClamp] are clamped and contribute no gradient, as in bceLoss.
+func bceLossDual(rPred dual, y float64) dual {
+	p := rPred
+	if p.v < bceClamp {
+		p = constant(bceClamp)
+	} else if p.v > 1-bceClamp {
+		p = constant(1 - bceClamp)
+	}
+	return p.log().scale(y).add(constant(1).sub(p).log().scale(1 - y)).scale(-1)
+}
+
+// clampSDual clamps stability to a minimum of 0.001.
+func clampSDual(s dual) dual {
+	if s.v < 0.001 {
+		return constant(0.001)
+	}
+	return s
+}
+
+// clampDDual clamps difficulty to [1, 10].
+func clampDDual(d dual) dual {
+	if d.v < 1 {
+		return constant(1)
+	}
+	if d.v > 10 {
+		return constant(10)
+	}
+	return d
+}
@@ -0,0 +1,141 @@
+package optimizer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sky-flux/flux"
+)
+
+// assertGradClose checks analytic against numerical gradients with a mixed
+// absolute/relative tolerance suitable for central differences at ε=1e-5.
+func assertGradClose(t *testing.T, analytic, numerical [21]float64) {
+	t.Helper()
+	for i := 0; i < 21; i++ {
+		diff := math.Abs(analytic[i] - numerical[i])
+		tol := 1e-4 + 1e-3*math.Abs(numerical[i])
+		if diff > tol {
+			t.Errorf("grad[%d]: analytic %g, numerical %g (diff %g)", i, analytic[i], numerical[i], diff)
+		}
+	}
+}
+
+// gradParams returns DefaultParameters nudged off the bounds, so that the
+// central differences of numericalGradient stay inside the valid region.
+func gradParams() [21]float64 {
+	p := flux.DefaultParameters
+	p[7] = 0.01 // default sits on its lower bound
+	return p
+}
+
+// --- analyticGradient ---
+
+func TestAnalyticGradientMatchesLoss(t *testing.T) {
+	data := formatRevlogs(generateSyntheticLogs(50, 8, 7))
+	loss, _ := analyticGradient(flux.DefaultParameters, data, 1)
+	want := computeBatchLoss(flux.DefaultParameters, data, 1)
+	assertFloatOpt(t, "analytic loss", loss, want)
+}
+
+func TestAnalyticGradientMatchesNumerical(t *testing.T) {
+	data := formatRevlogs(generateSyntheticLogs(50, 8, 7))
+	_, analytic := analyticGradient(gradParams(), data, 1)
+	numerical := numericalGradient(gradParams(), data, 1)
+	assertGradClose(t, analytic, numerical)
+}
+
+func TestAnalyticGradientMatchesNumericalAllBranches(t *testing.T) {
+	// Same-day Hard/Again/Good/Easy, cross-day Again/Hard/Easy: every
+	// rating-specific branch of the recurrences is exercised.
+	logs := []flux.ReviewLog{
+		{CardID: 1, Rating: flux.Hard, ReviewDatetime: t0},
+		{CardID: 1, Rating: flux.Again, ReviewDatetime: t0.Add(5 * time.Minute)},
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(15 * time.Minute)},
+		{CardID: 1, Rating: flux.Hard, ReviewDatetime: t0.Add(2 * 24 * time.Hour)},
+		{CardID: 1, Rating: flux.Again, ReviewDatetime: t0.Add(9 * 24 * time.Hour)},
+		{CardID: 1, Rating: flux.Easy, ReviewDatetime: t0.Add(9*24*time.Hour + time.Hour)},
+		{CardID: 1, Rating: flux.Easy, ReviewDatetime: t0.Add(12 * 24 * time.Hour)},
+		{CardID: 2, Rating: flux.Easy, ReviewDatetime: t0},
+		{CardID: 2, Rating: flux.Again, ReviewDatetime: t0.Add(30 * 24 * time.Hour)},
+		{CardID: 2, Rating: flux.Good, ReviewDatetime: t0.Add(31 * 24 * time.Hour)},
+	}
+	data := formatRevlogs(logs)
+
+	_, analytic := analyticGradient(gradParams(), data, 1)
+	numerical := numericalGradient(gradParams(), data, 1)
+	assertGradClose(t, analytic, numerical)
+}
+
+func TestAnalyticGradientNoCrossDay(t *testing.T) {
+	logs := []flux.ReviewLog{
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0},
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(5 * time.Minute)},
+	}
+	loss, grad := analyticGradient(flux.DefaultParameters, formatRevlogs(logs), 1)
+	if loss != 0 || grad != [21]float64{} {
+		t.Errorf("analyticGradient with no cross-day = (%f, %v), want zeros", loss, grad)
+	}
+}
+
+// --- clamping branches ---
+
+func TestClampSDual(t *testing.T) {
+	w := paramDuals(flux.DefaultParameters)
+	low := clampSDual(w[0].scale(1e-6))
+	if low.v != 0.001 || low.d != [21]float64{} {
+		t.Errorf("clampSDual below floor = %+v, want constant 0.001", low)
+	}
+	if got := clampSDual(w[1]); got != w[1] {
+		t.Errorf("clampSDual above floor should pass through")
+	}
+}
+
+func TestClampDDual(t *testing.T) {
+	w := paramDuals(flux.DefaultParameters)
+	if got := clampDDual(w[4].scale(0.01)); got.v != 1 || got.d != [21]float64{} {
+		t.Errorf("clampDDual low = %+v, want constant 1", got)
+	}
+	if got := clampDDual(w[4].scale(10)); got.v != 10 || got.d != [21]float64{} {
+		t.Errorf("clampDDual high = %+v, want constant 10", got)
+	}
+	if got := clampDDual(w[4]); got != w[4] {
+		t.Errorf("clampDDual in range should pass through")
+	}
+}
+
+func TestBceLossDualClamped(t *testing.T) {
+	w := paramDuals(flux.DefaultParameters)
+	for _, p := range []float64{0, 1} {
+		got := bceLossDual(w[0].scale(p/w[0].v), 1-p)
+		assertFloatOpt(t, "bceLossDual value", got.v, bceLoss(p, 1-p))
+		if got.d != [21]float64{} {
+			t.Errorf("bceLossDual(%v) gradient = %v, want zero when clamped", p, got.d)
+		}
+	}
+}
+
+func TestShortTermStabilityDualFloor(t *testing.T) {
+	// Large S drives SInc below 1; Good floors it at 1 so S passes through.
+	w := paramDuals(flux.DefaultParameters)
+	s := constant(1e6)
+	got := shortTermStabilityDual(&w, s, flux.Good)
+	assertFloatOpt(t, "floored S", got.v, 1e6)
+}
+
+func TestNextForgetStabilityDualLongBranch(t *testing.T) {
+	// Tiny S with high R makes the long-term term the smaller one.
+	w := paramDuals(flux.DefaultParameters)
+	got := nextForgetStabilityDual(&w, constant(5), constant(0.01), constant(0.99))
+	p := flux.DefaultParameters
+	long := p[11] * math.Pow(5, -p[12]) * (math.Pow(1.01, p[13]) - 1) * math.Exp(0.01*p[14])
+	assertFloatOpt(t, "long-term forget stability", got.v, long)
+}
+
+func TestNextForgetStabilityDualShortBranch(t *testing.T) {
+	// Tiny S with low R and D makes the short-term term the smaller one.
+	w := paramDuals(flux.DefaultParameters)
+	got := nextForgetStabilityDual(&w, constant(1), constant(0.001), constant(0.01))
+	p := flux.DefaultParameters
+	assertFloatOpt(t, "short-term forget stability", got.v, 0.001/math.Exp(p[17]*p[18]))
+}
@@ -16,9 +16,11 @@ func bceLoss(rPred, y float64) float64 {
 }
 
 // computeBatchLoss computes the average BCE loss over all cross-day reviews.
-// It creates a Scheduler from params and replays each card's review history.
+// It creates a Scheduler from params and replays each card's review history,
+// spreading cards across up to workers goroutines. Per-card sums are reduced
+// in card ID order, so the result does not depend on the worker count.
 // Returns 0 if there are no cross-day reviews.
-func computeBatchLoss(params [21]float64, data map[int64][]review) float64 {
+func computeBatchLoss(params [21]float64, data map[cardKey][]review, workers int) float64 {
 	s, err := flux.NewScheduler(flux.SchedulerConfig{
 		Parameters:     params,
 		DisableFuzzing: true,
@@ -27,12 +29,15 @@ func computeBatchLoss(params [21]float64, data map[int64][]review) float64 {
 		return 0
 	}
 
-	var totalLoss float64
-	var count int
+	cardIDs := sortedCardIDs(data)
+	losses := make([]float64, len(cardIDs))
+	counts := make([]int, len(cardIDs))
 
-	for cardID, reviews := range data {
-		card := flux.NewCard(cardID)
-		card.Due = reviews[0].reviewTime
+	parallelFor(len(cardIDs), workers, func(i int) {
+		cardID := cardIDs[i]
+		reviews := data[cardID]
+		card := flux.NewCardAt(cardID.id, reviews[0].reviewTime)
+		card.Key = cardID.key
 
 		for _, rev := range reviews {
 			// Compute retrievability BEFORE this review.
@@ -40,13 +45,20 @@ func computeBatchLoss(params [21]float64, data map[int64][]review) float64 {
 
 			// Only cross-day reviews contribute to loss.
 			if card.LastReview != nil && rev.elapsedDays >= 1.0 {
-				totalLoss += bceLoss(rPred, rev.label)
-				count++
+				losses[i] += bceLoss(rPred, rev.label)
+				counts[i]++
 			}
 
 			// Update card state.
 			card, _ = s.ReviewCard(card, rev.rating, rev.reviewTime)
 		}
+	})
+
+	var totalLoss float64
+	var count int
+	for i := range cardIDs {
+		totalLoss += losses[i]
+		count += counts[i]
 	}
 
 	if count == 0 {
@@ -59,18 +71,23 @@ const gradEps = 1e-5
 
 // numericalGradient computes the gradient of the batch loss w.r.t. each parameter
 // using central differences: dL/dw[i] ≈ (L(w[i]+ε) - L(w[i]-ε)) / (2ε).
-func numericalGradient(params [21]float64, data map[int64][]review) [21]float64 {
+// The 42 loss evaluations run on up to workers goroutines.
+// Training uses analyticGradient; this path is kept as a reference for tests.
+func numericalGradient(params [21]float64, data map[cardKey][]review, workers int) [21]float64 {
+	var losses [42]float64
+	parallelFor(len(losses), workers, func(k int) {
+		i, sign := k/2, 1.0
+		if k%2 == 1 {
+			sign = -1
+		}
+		p := params
+		p[i] += sign * gradEps
+		losses[k] = computeBatchLoss(p, data, 1)
+	})
+
 	var grad [21]float64
 	for i := 0; i < 21; i++ {
-		pPlus := params
-		pPlus[i] += gradEps
-		pMinus := params
-		pMinus[i] -= gradEps
-
-		lPlus := computeBatchLoss(pPlus, data)
-		lMinus := computeBatchLoss(pMinus, data)
-
-		grad[i] = (lPlus - lMinus) / (2 * gradEps)
+		grad[i] = (losses[2*i] - losses[2*i+1]) / (2 * gradEps)
 	}
 	return grad
 }
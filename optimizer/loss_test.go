@@ -55,7 +55,7 @@ func TestComputeBatchLossBasic(t *testing.T) {
 		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(3 * 24 * time.Hour)},
 	}
 	data := formatRevlogs(logs)
-	loss := computeBatchLoss(flux.DefaultParameters, data)
+	loss := computeBatchLoss(flux.DefaultParameters, data, 1)
 
 	// Loss should be finite and positive.
 	if math.IsNaN(loss) || math.IsInf(loss, 0) {
@@ -73,7 +73,7 @@ func TestComputeBatchLossNoCrossDay(t *testing.T) {
 		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(5 * time.Minute)},
 	}
 	data := formatRevlogs(logs)
-	loss := computeBatchLoss(flux.DefaultParameters, data)
+	loss := computeBatchLoss(flux.DefaultParameters, data, 1)
 	if loss != 0 {
 		t.Errorf("computeBatchLoss with no cross-day = %f, want 0", loss)
 	}
@@ -94,8 +94,8 @@ func TestComputeBatchLossAgainHigherLoss(t *testing.T) {
 	}
 	goodData := formatRevlogs(goodLogs)
 	againData := formatRevlogs(againLogs)
-	goodLoss := computeBatchLoss(flux.DefaultParameters, goodData)
-	againLoss := computeBatchLoss(flux.DefaultParameters, againData)
+	goodLoss := computeBatchLoss(flux.DefaultParameters, goodData, 1)
+	againLoss := computeBatchLoss(flux.DefaultParameters, againData, 1)
 	if againLoss <= goodLoss {
 		t.Errorf("Again loss %f should be > Good loss %f", againLoss, goodLoss)
 	}
@@ -113,7 +113,7 @@ func TestNumericalGradientDirection(t *testing.T) {
 		{CardID: 1, Rating: flux.Again, ReviewDatetime: t0.Add(4 * 24 * time.Hour)},
 	}
 	data := formatRevlogs(logs)
-	grad := numericalGradient(flux.DefaultParameters, data)
+	grad := numericalGradient(flux.DefaultParameters, data, 1)
 
 	// Gradient should be finite for all 21 parameters.
 	for i, g := range grad {
@@ -133,7 +133,7 @@ func TestNumericalGradientSymmetry(t *testing.T) {
 		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(5 * 24 * time.Hour)},
 	}
 	data := formatRevlogs(logs)
-	grad := numericalGradient(flux.DefaultParameters, data)
+	grad := numericalGradient(flux.DefaultParameters, data, 1)
 
 	// All gradients should be finite.
 	for i, g := range grad {
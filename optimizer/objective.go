@@ -0,0 +1,135 @@
+package optimizer
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// RetentionObjective selects what the optimal retention search optimizes.
+type RetentionObjective int
+
+const (
+	// ObjectiveCostPerMemorized minimizes review time per memorized card.
+	ObjectiveCostPerMemorized RetentionObjective = iota
+	// ObjectiveMaxMemorized maximizes the cards memorized at the end of the
+	// simulation. Pair it with RetentionConstraints.MaxDailyMinutes for the
+	// most knowledge within a daily time budget.
+	ObjectiveMaxMemorized
+	// ObjectiveMinWorkload minimizes the average daily review time. Pair it
+	// with RetentionConstraints.MinMemorizedFraction for the least time that
+	// reaches a knowledge target.
+	ObjectiveMinWorkload
+)
+
+var (
+	objectiveNames = [...]string{
+		ObjectiveCostPerMemorized: "CostPerMemorized",
+		ObjectiveMaxMemorized:     "MaxMemorized",
+		ObjectiveMinWorkload:      "MinWorkload",
+	}
+	objectiveByName = map[string]RetentionObjective{
+		"CostPerMemorized": ObjectiveCostPerMemorized,
+		"MaxMemorized":     ObjectiveMaxMemorized,
+		"MinWorkload":      ObjectiveMinWorkload,
+	}
+)
+
+// Compile-time interface checks.
+var (
+	_ fmt.Stringer             = RetentionObjective(0)
+	_ json.Marshaler           = RetentionObjective(0)
+	_ json.Unmarshaler         = (*RetentionObjective)(nil)
+	_ encoding.TextMarshaler   = RetentionObjective(0)
+	_ encoding.TextUnmarshaler = (*RetentionObjective)(nil)
+)
+
+func (o RetentionObjective) isValid() bool {
+	return o >= ObjectiveCostPerMemorized && o <= ObjectiveMinWorkload
+}
+
+// String returns the name of the objective ("CostPerMemorized",
+// "MaxMemorized", "MinWorkload").
+// For invalid values it returns "RetentionObjective(n)".
+func (o RetentionObjective) String() string {
+	if o.isValid() {
+		return objectiveNames[o]
+	}
+	return fmt.Sprintf("RetentionObjective(%d)", int(o))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (o RetentionObjective) MarshalText() ([]byte, error) {
+	if !o.isValid() {
+		return nil, fmt.Errorf("optimizer: invalid retention objective: %d", int(o))
+	}
+	return []byte(objectiveNames[o]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (o *RetentionObjective) UnmarshalText(text []byte) error {
+	v, ok := objectiveByName[string(text)]
+	if !ok {
+		return fmt.Errorf("optimizer: invalid retention objective: %q", text)
+	}
+	*o = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. RetentionObjective serializes as a JSON string.
+func (o RetentionObjective) MarshalJSON() ([]byte, error) {
+	text, err := o.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Expects a JSON string.
+func (o *RetentionObjective) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("optimizer: invalid retention objective: %s", data)
+	}
+	return o.UnmarshalText([]byte(str))
+}
+
+// RetentionConstraints limits which retentions the search may choose.
+// Zero values impose no limit.
+//
+// Both are measured on the simulation, which introduces all
+// OptimizerConfig.SimulatedCards cards on its first day. MaxDailyMinutes caps
+// the average review time per day over the SimulationDays, first-day
+// learning included; it is not a cap on the busiest day, and it scales with
+// SimulatedCards, so set that to the size of the learner's collection for a
+// budget in the learner's own minutes. MinMemorizedFraction is a share of the
+// simulated cards, so it holds for a collection of any size.
+type RetentionConstraints struct {
+	MaxDailyMinutes      float64 `json:"max_daily_minutes"`      // zero → no cap on average daily review time
+	MinMemorizedFraction float64 `json:"min_memorized_fraction"` // zero → no floor on the share of cards memorized at the end
+}
+
+// feasible reports whether p satisfies the constraints.
+func (c RetentionConstraints) feasible(p RetentionPoint) bool {
+	if c.MaxDailyMinutes > 0 && !(p.DailyMinutes <= c.MaxDailyMinutes) {
+		return false
+	}
+	return c.MinMemorizedFraction <= 0 || p.MemorizedFraction >= c.MinMemorizedFraction
+}
+
+// score returns the value the search minimizes for p under objective o and
+// constraints c: +Inf when p is infeasible.
+func score(o RetentionObjective, c RetentionConstraints, p RetentionPoint) float64 {
+	if !c.feasible(p) {
+		return math.Inf(1)
+	}
+	switch o {
+	case ObjectiveMaxMemorized:
+		return -p.Memorized
+	case ObjectiveMinWorkload:
+		return p.DailyMinutes
+	default:
+		return p.Cost
+	}
+}
@@ -0,0 +1,136 @@
+package optimizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/sky-flux/flux"
+)
+
+func TestRetentionObjectiveString(t *testing.T) {
+	tests := []struct {
+		o    RetentionObjective
+		want string
+	}{
+		{ObjectiveCostPerMemorized, "CostPerMemorized"},
+		{ObjectiveMaxMemorized, "MaxMemorized"},
+		{ObjectiveMinWorkload, "MinWorkload"},
+		{RetentionObjective(3), "RetentionObjective(3)"},
+	}
+	for _, tt := range tests {
+		if got := tt.o.String(); got != tt.want {
+			t.Errorf("RetentionObjective(%d).String() = %q, want %q", int(tt.o), got, tt.want)
+		}
+	}
+}
+
+func TestRetentionObjectiveJSONRoundTrip(t *testing.T) {
+	for _, o := range []RetentionObjective{ObjectiveCostPerMemorized, ObjectiveMaxMemorized, ObjectiveMinWorkload} {
+		data, err := json.Marshal(o)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", o, err)
+		}
+		if string(data) != `"`+o.String()+`"` {
+			t.Errorf("Marshal(%v) = %s", o, data)
+		}
+		var got RetentionObjective
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != o {
+			t.Errorf("round-trip: got %v, want %v", got, o)
+		}
+	}
+}
+
+func TestRetentionObjectiveJSONInvalid(t *testing.T) {
+	if _, err := json.Marshal(RetentionObjective(-1)); err == nil {
+		t.Error("json.Marshal(RetentionObjective(-1)) should return error")
+	}
+	for _, input := range []string{`"Unknown"`, `""`, `1`} {
+		var o RetentionObjective
+		if err := json.Unmarshal([]byte(input), &o); err == nil {
+			t.Errorf("json.Unmarshal(%s) should return error", input)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	p := RetentionPoint{Retention: 0.9, Cost: 80000, Memorized: 900, MemorizedFraction: 0.9, DailyMinutes: 4}
+	none := RetentionConstraints{}
+	for o, want := range map[RetentionObjective]float64{
+		ObjectiveCostPerMemorized: 80000,
+		ObjectiveMaxMemorized:     -900,
+		ObjectiveMinWorkload:      4,
+	} {
+		if got := score(o, none, p); got != want {
+			t.Errorf("score(%v) = %v, want %v", o, got, want)
+		}
+	}
+	for _, c := range []RetentionConstraints{{MaxDailyMinutes: 3}, {MinMemorizedFraction: 0.95}} {
+		if got := score(ObjectiveCostPerMemorized, c, p); !math.IsInf(got, 1) {
+			t.Errorf("score under %+v = %v, want +Inf", c, got)
+		}
+	}
+	if got := score(ObjectiveMinWorkload, RetentionConstraints{MaxDailyMinutes: 5, MinMemorizedFraction: 0.85}, p); got != 4 {
+		t.Errorf("score under satisfied constraints = %v, want 4", got)
+	}
+}
+
+func TestComputeRetentionCurveObjectives(t *testing.T) {
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	curve := func(cfg OptimizerConfig) RetentionCurve {
+		t.Helper()
+		c, err := NewOptimizer(cfg).ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs)
+		if err != nil {
+			t.Fatalf("%+v: %v", cfg, err)
+		}
+		return c
+	}
+	base := curve(OptimizerConfig{})
+
+	// Most knowledge within a daily budget: above the cost optimum, but
+	// not the top of the range.
+	budget := curve(OptimizerConfig{Objective: ObjectiveMaxMemorized, Constraints: RetentionConstraints{MaxDailyMinutes: 4}})
+	if o := budget.Optimal; o.DailyMinutes > 4 || o.Memorized <= base.Optimal.Memorized || o.Retention >= 0.95 {
+		t.Errorf("max memorized within 4 min/day = %+v, cost optimum %+v", o, base.Optimal)
+	}
+
+	// Least time reaching a knowledge target.
+	target := curve(OptimizerConfig{Objective: ObjectiveMinWorkload, Constraints: RetentionConstraints{MinMemorizedFraction: 0.95}})
+	if o := target.Optimal; o.MemorizedFraction < 0.95 || o.Retention >= 0.95 {
+		t.Errorf("min workload for 95%% memorized = %+v", o)
+	}
+
+	// The memorized share does not depend on how many cards are simulated.
+	half := curve(OptimizerConfig{Objective: ObjectiveMinWorkload, Constraints: RetentionConstraints{MinMemorizedFraction: 0.95}, SimulatedCards: 500})
+	if o := half.Optimal; o.MemorizedFraction < 0.95 || math.Abs(o.Retention-target.Optimal.Retention) > 0.02 {
+		t.Errorf("min workload for 95%% memorized of 500 cards = %+v, of 1000 = %+v", o, target.Optimal)
+	}
+}
+
+func TestComputeRetentionCurveInfeasible(t *testing.T) {
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	o := NewOptimizer(OptimizerConfig{Constraints: RetentionConstraints{MaxDailyMinutes: 1}})
+	curve, err := o.ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs)
+	if !errors.Is(err, ErrNoFeasibleRetention) {
+		t.Errorf("error = %v, want ErrNoFeasibleRetention", err)
+	}
+	if len(curve.Points) == 0 {
+		t.Error("an infeasible search should still return the curve")
+	}
+	if _, err := o.ComputeOptimalRetention(context.Background(), flux.DefaultParameters, logs); !errors.Is(err, ErrNoFeasibleRetention) {
+		t.Errorf("ComputeOptimalRetention error = %v, want ErrNoFeasibleRetention", err)
+	}
+}
+
+func TestComputeRetentionCurveInvalidObjective(t *testing.T) {
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	o := NewOptimizer(OptimizerConfig{Objective: RetentionObjective(9)})
+	if _, err := o.ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs); !errors.Is(err, ErrInvalidObjective) {
+		t.Errorf("error = %v, want ErrInvalidObjective", err)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"errors"
 	"math"
 	"math/rand"
-	"sort"
+	"runtime"
 
 	"github.com/sky-flux/flux"
 )
@@ -25,26 +25,52 @@ type OptimizerConfig struct {
 	MiniBatchSize int     `json:"mini_batch_size"` // default 512
 	LearningRate  float64 `json:"learning_rate"`   // default 0.04
 	MaxSeqLen     int     `json:"max_seq_len"`     // default 64
+	Workers       int     `json:"workers"`         // default runtime.GOMAXPROCS(0)
+	MinRetention  float64 `json:"min_retention"`   // default 0.70; lower end of the retention search
+	MaxRetention  float64 `json:"max_retention"`   // default 0.95; upper end of the retention search
+
+	SimulatedCards int `json:"simulated_cards"` // default 1000; cards per retention simulation
+	SimulationDays int `json:"simulation_days"` // default 365; days per retention simulation
+
+	Objective   RetentionObjective   `json:"objective"`   // default ObjectiveCostPerMemorized
+	Constraints RetentionConstraints `json:"constraints"` // default no constraints
 }
 
 // Optimizer trains FSRS parameters from review logs using mini-batch
 // gradient descent with Adam and cosine annealing learning rate.
 type Optimizer struct {
-	epochs        int
-	miniBatchSize int
-	learningRate  float64
-	maxSeqLen     int
+	epochs         int
+	miniBatchSize  int
+	learningRate   float64
+	maxSeqLen      int
+	workers        int
+	minRetention   float64
+	maxRetention   float64
+	simulatedCards int
+	simulationDays int
+	objective      RetentionObjective
+	constraints    RetentionConstraints
 }
 
 // NewOptimizer creates an Optimizer with the given config.
 // Zero-valued fields receive defaults: Epochs=5, MiniBatchSize=512,
-// LearningRate=0.04, MaxSeqLen=64.
+// LearningRate=0.04, MaxSeqLen=64, Workers=runtime.GOMAXPROCS(0),
+// MinRetention=0.70, MaxRetention=0.95, SimulatedCards=1000,
+// SimulationDays=365.
+// Trained parameters are bit-identical for every Workers value.
 func NewOptimizer(cfg OptimizerConfig) *Optimizer {
 	o := &Optimizer{
-		epochs:        cfg.Epochs,
-		miniBatchSize: cfg.MiniBatchSize,
-		learningRate:  cfg.LearningRate,
-		maxSeqLen:     cfg.MaxSeqLen,
+		epochs:         cfg.Epochs,
+		miniBatchSize:  cfg.MiniBatchSize,
+		learningRate:   cfg.LearningRate,
+		maxSeqLen:      cfg.MaxSeqLen,
+		workers:        cfg.Workers,
+		minRetention:   cfg.MinRetention,
+		maxRetention:   cfg.MaxRetention,
+		simulatedCards: cfg.SimulatedCards,
+		simulationDays: cfg.SimulationDays,
+		objective:      cfg.Objective,
+		constraints:    cfg.Constraints,
 	}
 	if o.epochs == 0 {
 		o.epochs = 5
@@ -58,12 +84,29 @@ func NewOptimizer(cfg OptimizerConfig) *Optimizer {
 	if o.maxSeqLen == 0 {
 		o.maxSeqLen = 64
 	}
+	if o.workers == 0 {
+		o.workers = runtime.GOMAXPROCS(0)
+	}
+	if o.minRetention == 0 {
+		o.minRetention = 0.70
+	}
+	if o.maxRetention == 0 {
+		o.maxRetention = 0.95
+	}
+	if o.simulatedCards == 0 {
+		o.simulatedCards = 1000
+	}
+	if o.simulationDays == 0 {
+		o.simulationDays = 365
+	}
 	return o
 }
 
 // ComputeOptimalParameters optimizes FSRS parameters from review logs.
-// It starts from DefaultParameters and uses mini-batch gradient descent
-// (numerical central differences) with Adam optimizer and cosine annealing LR.
+// It starts from DefaultParameters with w[0..3] pretrained as in
+// PretrainParameters, then uses mini-batch gradient descent
+// (exact gradients via forward-mode automatic differentiation) with Adam
+// optimizer and cosine annealing LR.
 //
 // Returns ErrEmptyLogs if logs is empty, or ErrInsufficientData (along with
 // DefaultParameters) if cross-day reviews are fewer than MiniBatchSize.
@@ -87,18 +130,15 @@ func (o *Optimizer) ComputeOptimalParameters(ctx context.Context, logs []flux.Re
 		return flux.DefaultParameters, ErrInsufficientData
 	}
 
-	params := flux.DefaultParameters
+	// Pretrain w[0..3] from first-review outcomes before gradient descent.
+	params, _ := pretrain(data)
 	tMax := int(math.Ceil(float64(numReviews)/float64(o.miniBatchSize))) * o.epochs
 	adam := NewAdam(o.learningRate)
 	ca := NewCosineAnnealing(o.learningRate, tMax)
 	rng := rand.New(rand.NewSource(42))
 
 	// Sorted card IDs for deterministic shuffle.
-	cardIDs := make([]int64, 0, len(data))
-	for id := range data {
-		cardIDs = append(cardIDs, id)
-	}
-	sort.Slice(cardIDs, func(i, j int) bool { return cardIDs[i] < cardIDs[j] })
+	cardIDs := sortedCardIDs(data)
 
 	bestParams := params
 	bestLoss := math.Inf(1)
@@ -112,7 +152,7 @@ func (o *Optimizer) ComputeOptimalParameters(ctx context.Context, logs []flux.Re
 			cardIDs[i], cardIDs[j] = cardIDs[j], cardIDs[i]
 		})
 
-		batchData := make(map[int64][]review)
+		batchData := make(map[cardKey][]review)
 		crossDayCount := 0
 
 		for _, cardID := range cardIDs {
@@ -126,20 +166,20 @@ func (o *Optimizer) ComputeOptimalParameters(ctx context.Context, logs []flux.Re
 			}
 
 			if crossDayCount >= o.miniBatchSize {
-				grad := numericalGradient(params, batchData)
+				_, grad := analyticGradient(params, batchData, o.workers)
 				adam.SetLR(ca.LR())
 				params = adam.Update(params, grad)
 				params = clampParams(params)
 				ca.Step()
 
-				batchData = make(map[int64][]review)
+				batchData = make(map[cardKey][]review)
 				crossDayCount = 0
 			}
 		}
 
 		// Handle remaining reviews at end of epoch.
 		if crossDayCount > 0 {
-			grad := numericalGradient(params, batchData)
+			_, grad := analyticGradient(params, batchData, o.workers)
 			adam.SetLR(ca.LR())
 			params = adam.Update(params, grad)
 			params = clampParams(params)
@@ -147,7 +187,7 @@ func (o *Optimizer) ComputeOptimalParameters(ctx context.Context, logs []flux.Re
 		}
 
 		// Track best parameters by epoch loss.
-		epochLoss := computeBatchLoss(params, data)
+		epochLoss := computeBatchLoss(params, data, o.workers)
 		if epochLoss < bestLoss {
 			bestLoss = epochLoss
 			bestParams = params
@@ -161,7 +201,7 @@ func (o *Optimizer) ComputeOptimalParameters(ctx context.Context, logs []flux.Re
 // This is a convenience wrapper that preprocesses the review logs.
 func (o *Optimizer) ComputeBatchLoss(params [21]float64, logs []flux.ReviewLog) float64 {
 	data := formatRevlogs(logs)
-	return computeBatchLoss(params, data)
+	return computeBatchLoss(params, data, o.workers)
 }
 
 // clampParams constrains each parameter to [LowerBounds, UpperBounds].
@@ -3,6 +3,7 @@ package optimizer
 import (
 	"context"
 	"math/rand"
+	"runtime"
 	"testing"
 	"time"
 
@@ -75,6 +76,15 @@ func TestNewOptimizerDefaults(t *testing.T) {
 	if o.maxSeqLen != 64 {
 		t.Errorf("maxSeqLen = %d, want 64", o.maxSeqLen)
 	}
+	if o.workers != runtime.GOMAXPROCS(0) {
+		t.Errorf("workers = %d, want %d", o.workers, runtime.GOMAXPROCS(0))
+	}
+	if o.minRetention != 0.70 || o.maxRetention != 0.95 {
+		t.Errorf("retention range = [%v, %v], want [0.70, 0.95]", o.minRetention, o.maxRetention)
+	}
+	if o.simulatedCards != 1000 || o.simulationDays != 365 {
+		t.Errorf("simulation = %d cards, %d days, want 1000, 365", o.simulatedCards, o.simulationDays)
+	}
 }
 
 func TestNewOptimizerCustom(t *testing.T) {
@@ -83,6 +93,12 @@ func TestNewOptimizerCustom(t *testing.T) {
 		MiniBatchSize: 256,
 		LearningRate:  0.01,
 		MaxSeqLen:     32,
+		Workers:       3,
+		MinRetention:  0.8,
+		MaxRetention:  0.9,
+
+		SimulatedCards: 200,
+		SimulationDays: 90,
 	})
 	if o.epochs != 10 {
 		t.Errorf("epochs = %d, want 10", o.epochs)
@@ -96,6 +112,15 @@ func TestNewOptimizerCustom(t *testing.T) {
 	if o.maxSeqLen != 32 {
 		t.Errorf("maxSeqLen = %d, want 32", o.maxSeqLen)
 	}
+	if o.workers != 3 {
+		t.Errorf("workers = %d, want 3", o.workers)
+	}
+	if o.minRetention != 0.8 || o.maxRetention != 0.9 {
+		t.Errorf("retention range = [%v, %v], want [0.8, 0.9]", o.minRetention, o.maxRetention)
+	}
+	if o.simulatedCards != 200 || o.simulationDays != 90 {
+		t.Errorf("simulation = %d cards, %d days, want 200, 90", o.simulatedCards, o.simulationDays)
+	}
 }
 
 // --- ComputeOptimalParameters ---
@@ -130,14 +155,14 @@ func TestOptimizerLossDecreases(t *testing.T) {
 	o := NewOptimizer(OptimizerConfig{Epochs: 3})
 
 	data := formatRevlogs(logs)
-	initialLoss := computeBatchLoss(flux.DefaultParameters, data)
+	initialLoss := computeBatchLoss(flux.DefaultParameters, data, 1)
 
 	optimized, err := o.ComputeOptimalParameters(context.Background(), logs)
 	if err != nil {
 		t.Fatalf("ComputeOptimalParameters: %v", err)
 	}
 
-	optimizedLoss := computeBatchLoss(optimized, data)
+	optimizedLoss := computeBatchLoss(optimized, data, 1)
 	// Optimized loss should not be significantly worse than initial.
 	if optimizedLoss > initialLoss*1.01 {
 		t.Errorf("optimized loss %f > initial loss %f * 1.01", optimizedLoss, initialLoss)
@@ -188,6 +213,26 @@ func TestOptimizerMaxSeqLen(t *testing.T) {
 	}
 }
 
+func TestOptimizerWorkersBitIdentical(t *testing.T) {
+	logs := generateSyntheticLogs(300, 10, 42)
+
+	var want [21]float64
+	for i, workers := range []int{1, 2, 8} {
+		o := NewOptimizer(OptimizerConfig{Epochs: 2, Workers: workers})
+		got, err := o.ComputeOptimalParameters(context.Background(), logs)
+		if err != nil {
+			t.Fatalf("Workers=%d: %v", workers, err)
+		}
+		if i == 0 {
+			want = got
+			continue
+		}
+		if got != want {
+			t.Errorf("Workers=%d: params differ from Workers=1\ngot  %v\nwant %v", workers, got, want)
+		}
+	}
+}
+
 // --- ComputeBatchLoss (public) ---
 
 func TestComputeBatchLossPublic(t *testing.T) {
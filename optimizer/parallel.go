@@ -0,0 +1,49 @@
+package optimizer
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelFor calls fn(i) for every i in [0, n) on up to workers goroutines.
+// Work is handed out index by index; callers write results into per-index
+// slots and reduce them in index order, which keeps floating-point sums
+// bit-identical regardless of the worker count.
+func parallelFor(n, workers int, fn func(i int)) {
+	workers = min(workers, n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// sortedCardIDs returns the cards of data in ascending order, fixing the
+// order in which per-card results are reduced.
+func sortedCardIDs(data map[cardKey][]review) []cardKey {
+	ids := make([]cardKey, 0, len(data))
+	for id := range data {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, compareCardKeys)
+	return ids
+}
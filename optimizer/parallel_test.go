@@ -0,0 +1,63 @@
+package optimizer
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sky-flux/flux"
+)
+
+func TestParallelForVisitsEachIndexOnce(t *testing.T) {
+	for _, workers := range []int{0, 1, 4, 100} {
+		var hits [50]atomic.Int32
+		parallelFor(len(hits), workers, func(i int) {
+			hits[i].Add(1)
+		})
+		for i := range hits {
+			if n := hits[i].Load(); n != 1 {
+				t.Errorf("workers=%d: index %d visited %d times, want 1", workers, i, n)
+			}
+		}
+	}
+}
+
+func TestParallelForEmpty(t *testing.T) {
+	parallelFor(0, 4, func(int) {
+		t.Error("fn called for n=0")
+	})
+}
+
+func TestSortedCardIDs(t *testing.T) {
+	data := map[cardKey][]review{{id: 3}: nil, {id: 1, key: "b"}: nil, {id: 1, key: "a"}: nil, {id: 2}: nil}
+	got := sortedCardIDs(data)
+	want := []cardKey{{id: 1, key: "a"}, {id: 1, key: "b"}, {id: 2}, {id: 3}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("sortedCardIDs = %v, want %v", got, want)
+	}
+}
+
+func TestBatchLossWorkersBitIdentical(t *testing.T) {
+	data := formatRevlogs(generateSyntheticLogs(200, 8, 3))
+	want := computeBatchLoss(flux.DefaultParameters, data, 1)
+	for _, workers := range []int{2, 8} {
+		if got := computeBatchLoss(flux.DefaultParameters, data, workers); got != want {
+			t.Errorf("workers=%d: loss %v, want %v", workers, got, want)
+		}
+	}
+}
+
+func TestGradientWorkersBitIdentical(t *testing.T) {
+	data := formatRevlogs(generateSyntheticLogs(100, 8, 3))
+	wantLoss, wantGrad := analyticGradient(flux.DefaultParameters, data, 1)
+	wantNum := numericalGradient(flux.DefaultParameters, data, 1)
+	for _, workers := range []int{2, 8} {
+		loss, grad := analyticGradient(flux.DefaultParameters, data, workers)
+		if loss != wantLoss || grad != wantGrad {
+			t.Errorf("workers=%d: analytic gradient differs from workers=1", workers)
+		}
+		if num := numericalGradient(flux.DefaultParameters, data, workers); num != wantNum {
+			t.Errorf("workers=%d: numerical gradient differs from workers=1", workers)
+		}
+	}
+}
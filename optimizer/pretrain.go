@@ -0,0 +1,218 @@
+package optimizer
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/sky-flux/flux"
+)
+
+// PretrainParameters fits the initial stabilities w[0..3] from how well
+// cards were recalled at their first cross-day review, as fsrs-rs does
+// before gradient descent. The other parameters are DefaultParameters.
+//
+// For each first rating, the recall rate against days elapsed is fit with
+// the forgetting curve, pulled gently toward the default stability. Ratings
+// whose stabilities come out of order are pooled to their count-weighted
+// mean, and ratings with no data are filled in from the others following
+// the shape of the defaults. It needs far less data than
+// ComputeOptimalParameters, so it also serves small collections on its own.
+//
+// Returns ErrEmptyLogs if logs is empty, or ErrInsufficientData (along with
+// DefaultParameters) if no card has a cross-day review after its first.
+func (o *Optimizer) PretrainParameters(logs []flux.ReviewLog) ([21]float64, error) {
+	if len(logs) == 0 {
+		return [21]float64{}, ErrEmptyLogs
+	}
+	params, ok := pretrain(formatRevlogs(logs))
+	if !ok {
+		return flux.DefaultParameters, ErrInsufficientData
+	}
+	return params, nil
+}
+
+// initStabilityMax caps pretrained initial stabilities, as in fsrs-rs.
+const initStabilityMax = 100.0
+
+// recallGroup counts first cross-day reviews at one elapsed day count.
+type recallGroup struct {
+	days     float64
+	n        float64
+	recalled float64
+}
+
+// pretrain returns DefaultParameters with w[0..3] fit to data, and whether
+// any first rating had data to fit.
+func pretrain(data map[cardKey][]review) ([21]float64, bool) {
+	groups := firstRecalls(data)
+
+	var s0, counts [4]float64
+	var known [4]bool
+	for r, g := range groups {
+		if len(g) == 0 {
+			continue
+		}
+		s0[r] = fitInitialStability(g, flux.DefaultParameters[r])
+		for _, rg := range g {
+			counts[r] += rg.n
+		}
+		known[r] = true
+	}
+	if known == [4]bool{} {
+		return flux.DefaultParameters, false
+	}
+
+	makeMonotonic(&s0, counts, known)
+	fillMissing(&s0, known)
+
+	params := flux.DefaultParameters
+	for r := range s0 {
+		params[r] = min(max(s0[r], flux.LowerBounds[r]), flux.UpperBounds[r], initStabilityMax)
+	}
+	return params, true
+}
+
+// firstRecalls groups, by first rating, each card's first cross-day review
+// by whole days elapsed, in ascending order of days.
+func firstRecalls(data map[cardKey][]review) [4][]recallGroup {
+	var byDays [4]map[float64]*recallGroup
+	for _, reviews := range data {
+		first := reviews[0].rating
+		for _, rev := range reviews[1:] {
+			if rev.elapsedDays < 1 {
+				continue
+			}
+			r := int(first - flux.Again)
+			if byDays[r] == nil {
+				byDays[r] = make(map[float64]*recallGroup)
+			}
+			days := math.Round(rev.elapsedDays)
+			g := byDays[r][days]
+			if g == nil {
+				g = &recallGroup{days: days}
+				byDays[r][days] = g
+			}
+			g.n++
+			g.recalled += rev.label
+			break
+		}
+	}
+
+	var groups [4][]recallGroup
+	for r, m := range byDays {
+		for _, g := range m {
+			groups[r] = append(groups[r], *g)
+		}
+		slices.SortFunc(groups[r], func(a, b recallGroup) int {
+			return cmp.Compare(a.days, b.days)
+		})
+	}
+	return groups
+}
+
+// fitInitialStability returns the stability minimizing the log loss of the
+// forgetting curve over groups, plus an L1 pull toward init. The loss is
+// minimized by golden-section search over log stability.
+func fitInitialStability(groups []recallGroup, init float64) float64 {
+	const eps = 1e-6
+	loss := func(s float64) float64 {
+		l := math.Abs(s-init) / 16
+		for _, g := range groups {
+			p := min(max(forgettingCurve(g.days, s), eps), 1-eps)
+			l -= g.recalled*math.Log(p) + (g.n-g.recalled)*math.Log(1-p)
+		}
+		return l
+	}
+
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := math.Log(flux.LowerBounds[0]), math.Log(initStabilityMax)
+	x1, x2 := b-invPhi*(b-a), a+invPhi*(b-a)
+	f1, f2 := loss(math.Exp(x1)), loss(math.Exp(x2))
+	for range 100 {
+		if f1 < f2 {
+			b, x2, f2 = x2, x1, f1
+			x1 = b - invPhi*(b-a)
+			f1 = loss(math.Exp(x1))
+		} else {
+			a, x1, f1 = x1, x2, f2
+			x2 = a + invPhi*(b-a)
+			f2 = loss(math.Exp(x2))
+		}
+	}
+	return math.Exp((a + b) / 2)
+}
+
+// forgettingCurve is the FSRS v6 retrievability after t days at stability s,
+// with the default decay.
+func forgettingCurve(t, s float64) float64 {
+	decay := -flux.DefaultParameters[20]
+	factor := math.Pow(0.9, 1/decay) - 1
+	return math.Pow(1+factor*t/s, decay)
+}
+
+// makeMonotonic orders the known stabilities so a better first rating never
+// has a lower one, by isotonic regression weighted by review counts: runs
+// of out-of-order ratings are pooled (pool-adjacent-violators) and share
+// their count-weighted mean.
+func makeMonotonic(s0 *[4]float64, counts [4]float64, known [4]bool) {
+	type block struct {
+		mean, weight float64
+		ratings      []int
+	}
+	var blocks []block
+	for r := range s0 {
+		if !known[r] {
+			continue
+		}
+		blocks = append(blocks, block{mean: s0[r], weight: counts[r], ratings: []int{r}})
+		for n := len(blocks); n > 1 && blocks[n-2].mean > blocks[n-1].mean; n = len(blocks) {
+			prev, last := blocks[n-2], blocks[n-1]
+			weight := prev.weight + last.weight
+			blocks[n-2] = block{
+				mean:    (prev.mean*prev.weight + last.mean*last.weight) / weight,
+				weight:  weight,
+				ratings: append(prev.ratings, last.ratings...),
+			}
+			blocks = blocks[:n-1]
+		}
+	}
+	for _, b := range blocks {
+		for _, r := range b.ratings {
+			s0[r] = b.mean
+		}
+	}
+}
+
+// fillMissing estimates the stabilities of unknown ratings from the known
+// ones, following the shape of DefaultParameters in log space: between two
+// known ratings it interpolates, beyond them it keeps the default ratio to
+// the nearest one.
+func fillMissing(s0 *[4]float64, known [4]bool) {
+	logDefault := func(r int) float64 { return math.Log(flux.DefaultParameters[r]) }
+	for r := range s0 {
+		if known[r] {
+			continue
+		}
+		lo, hi := -1, -1
+		for k := r - 1; k >= 0 && lo < 0; k-- {
+			if known[k] {
+				lo = k
+			}
+		}
+		for k := r + 1; k < 4 && hi < 0; k++ {
+			if known[k] {
+				hi = k
+			}
+		}
+		switch {
+		case lo >= 0 && hi >= 0:
+			frac := (logDefault(r) - logDefault(lo)) / (logDefault(hi) - logDefault(lo))
+			s0[r] = math.Exp(math.Log(s0[lo]) + frac*(math.Log(s0[hi])-math.Log(s0[lo])))
+		case lo >= 0:
+			s0[r] = s0[lo] * math.Exp(logDefault(r)-logDefault(lo))
+		default:
+			s0[r] = s0[hi] * math.Exp(logDefault(r)-logDefault(hi))
+		}
+	}
+}
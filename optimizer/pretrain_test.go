@@ -0,0 +1,257 @@
+package optimizer
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sky-flux/flux"
+)
+
+// recallData builds cards first rated first and reviewed once after each of
+// days, recalled at the forgetting curve's rate for stability s.
+func recallData(first flux.Rating, s float64, perDay int, days ...float64) map[cardKey][]review {
+	data := make(map[cardKey][]review)
+	var id int64
+	for _, d := range days {
+		recalled := int(math.Round(forgettingCurve(d, s) * float64(perDay)))
+		for i := range perDay {
+			label, rating := 1.0, flux.Good
+			if i >= recalled {
+				label, rating = 0, flux.Again
+			}
+			data[cardKey{id: id}] = []review{
+				{rating: first, label: labelOf(first)},
+				{rating: rating, elapsedDays: d, label: label},
+			}
+			id++
+		}
+	}
+	return data
+}
+
+// labelOf returns the training label of rating r.
+func labelOf(r flux.Rating) float64 {
+	if r == flux.Again {
+		return 0
+	}
+	return 1
+}
+
+// merge renumbers the cards of maps into one data set.
+func merge(maps ...map[cardKey][]review) map[cardKey][]review {
+	out := make(map[cardKey][]review)
+	for _, m := range maps {
+		for _, v := range m {
+			out[cardKey{id: int64(len(out))}] = v
+		}
+	}
+	return out
+}
+
+func TestFirstRecalls(t *testing.T) {
+	data := map[cardKey][]review{
+		{id: 1}: {{rating: flux.Good, label: 1}, {rating: flux.Good, elapsedDays: 0.1, label: 1}, {rating: flux.Again, elapsedDays: 2.2, label: 0}, {rating: flux.Good, elapsedDays: 5, label: 1}},
+		{id: 2}: {{rating: flux.Good, label: 1}, {rating: flux.Good, elapsedDays: 1.9, label: 1}},
+		{id: 3}: {{rating: flux.Good, label: 1}, {rating: flux.Good, elapsedDays: 0.6, label: 1}},
+		{id: 4}: {{rating: flux.Again, label: 0}, {rating: flux.Hard, elapsedDays: 1, label: 1}},
+	}
+	groups := firstRecalls(data)
+
+	want := []recallGroup{{days: 2, n: 2, recalled: 1}}
+	if len(groups[2]) != 1 || groups[2][0] != want[0] {
+		t.Errorf("Good groups = %+v, want %+v", groups[2], want)
+	}
+	if len(groups[0]) != 1 || groups[0][0] != (recallGroup{days: 1, n: 1, recalled: 1}) {
+		t.Errorf("Again groups = %+v", groups[0])
+	}
+	if len(groups[1]) != 0 || len(groups[3]) != 0 {
+		t.Errorf("Hard/Easy groups = %+v, %+v, want none", groups[1], groups[3])
+	}
+}
+
+func TestFirstRecallsSortedByDays(t *testing.T) {
+	groups := firstRecalls(recallData(flux.Easy, 10, 3, 7, 1, 30, 3))
+	var prev float64
+	for _, g := range groups[3] {
+		if g.days <= prev {
+			t.Fatalf("groups not ascending by days: %+v", groups[3])
+		}
+		prev = g.days
+	}
+	if len(groups[3]) != 4 {
+		t.Errorf("got %d groups, want 4", len(groups[3]))
+	}
+}
+
+func TestFitInitialStabilityRecoversStability(t *testing.T) {
+	for _, s := range []float64{0.5, 3, 20} {
+		groups := firstRecalls(recallData(flux.Good, s, 1000, 1, 2, 3, 5, 8, 13, 21))[2]
+		got := fitInitialStability(groups, s)
+		if math.Abs(got-s)/s > 0.05 {
+			t.Errorf("fit with S0=%v: got %v", s, got)
+		}
+	}
+}
+
+func TestFitInitialStabilityBounded(t *testing.T) {
+	allRecalled := []recallGroup{{days: 100, n: 1000, recalled: 1000}}
+	if got := fitInitialStability(allRecalled, 1); got > initStabilityMax || got < initStabilityMax*0.99 {
+		t.Errorf("all recalled: got %v, want near %v", got, initStabilityMax)
+	}
+	noneRecalled := []recallGroup{{days: 1, n: 1000}}
+	if got := fitInitialStability(noneRecalled, 1); got < flux.LowerBounds[0] || got > flux.LowerBounds[0]*1.01 {
+		t.Errorf("none recalled: got %v, want near %v", got, flux.LowerBounds[0])
+	}
+}
+
+func TestPretrainFitsAllRatings(t *testing.T) {
+	want := [4]float64{0.4, 1.5, 4, 12}
+	data := merge(
+		recallData(flux.Again, want[0], 500, 1, 2, 4),
+		recallData(flux.Hard, want[1], 500, 1, 2, 4),
+		recallData(flux.Good, want[2], 500, 1, 3, 7),
+		recallData(flux.Easy, want[3], 500, 3, 7, 14),
+	)
+	params, ok := pretrain(data)
+	if !ok {
+		t.Fatal("pretrain reported no data")
+	}
+	for r := range want {
+		if math.Abs(params[r]-want[r])/want[r] > 0.1 {
+			t.Errorf("w[%d] = %v, want ≈ %v", r, params[r], want[r])
+		}
+	}
+	for i := 4; i < 21; i++ {
+		if params[i] != flux.DefaultParameters[i] {
+			t.Errorf("w[%d] = %v, want default %v", i, params[i], flux.DefaultParameters[i])
+		}
+	}
+	if err := flux.ValidateParameters(params); err != nil {
+		t.Errorf("pretrained parameters invalid: %v", err)
+	}
+}
+
+func TestPretrainNoData(t *testing.T) {
+	data := map[cardKey][]review{
+		{id: 1}: {{rating: flux.Good, label: 1}, {rating: flux.Good, elapsedDays: 0.2, label: 1}},
+	}
+	params, ok := pretrain(data)
+	if ok || params != flux.DefaultParameters {
+		t.Errorf("pretrain = %v, %v; want DefaultParameters, false", params, ok)
+	}
+}
+
+func TestMakeMonotonic(t *testing.T) {
+	all := [4]bool{true, true, true, true}
+	tests := []struct {
+		name   string
+		s0     [4]float64
+		counts [4]float64
+		known  [4]bool
+		want   [4]float64
+	}{
+		{"ordered", [4]float64{1, 2, 3, 4}, [4]float64{1, 1, 1, 1}, all, [4]float64{1, 2, 3, 4}},
+		{"pair pooled", [4]float64{1, 5, 3, 4}, [4]float64{1, 1, 9, 1}, all, [4]float64{1, 3.2, 3.2, 4}},
+		{"pair pooled toward the larger count", [4]float64{1, 5, 3, 6}, [4]float64{1, 9, 1, 1}, all, [4]float64{1, 4.8, 4.8, 6}},
+		// A single pass over pairs would fix (1,2) and then break (0,1).
+		{"run pooled", [4]float64{3, 1, 2, 5}, [4]float64{10, 1, 1, 1}, all, [4]float64{2.75, 2.75, 2.75, 5}},
+		{"all pooled", [4]float64{4, 3, 2, 1}, [4]float64{1, 1, 1, 1}, all, [4]float64{2.5, 2.5, 2.5, 2.5}},
+		{"unknown skipped", [4]float64{1, 0, 3, 2}, [4]float64{1, 0, 1, 5}, [4]bool{true, false, true, true}, [4]float64{1, 0, 13.0 / 6, 13.0 / 6}},
+	}
+	for _, tt := range tests {
+		s0 := tt.s0
+		makeMonotonic(&s0, tt.counts, tt.known)
+		for r := range s0 {
+			if math.Abs(s0[r]-tt.want[r]) > 1e-12 {
+				t.Errorf("%s: got %v, want %v", tt.name, s0, tt.want)
+				break
+			}
+		}
+		for r := 1; r < 4; r++ {
+			if tt.known[r] && tt.known[r-1] && s0[r] < s0[r-1] {
+				t.Errorf("%s: not monotonic: %v", tt.name, s0)
+			}
+		}
+	}
+}
+
+func TestFillMissing(t *testing.T) {
+	d := flux.DefaultParameters
+	tests := []struct {
+		name  string
+		s0    [4]float64
+		known [4]bool
+		want  [4]float64
+	}{
+		{"defaults reproduce defaults", [4]float64{d[0], 0, d[2], 0}, [4]bool{true, false, true, false}, [4]float64{d[0], d[1], d[2], d[3]}},
+		{"scaled below", [4]float64{0, 0, 2 * d[2], 0}, [4]bool{false, false, true, false}, [4]float64{2 * d[0], 2 * d[1], 2 * d[2], 2 * d[3]}},
+		{"scaled above", [4]float64{0.5 * d[0], 0, 0, 0}, [4]bool{true, false, false, false}, [4]float64{0.5 * d[0], 0.5 * d[1], 0.5 * d[2], 0.5 * d[3]}},
+	}
+	for _, tt := range tests {
+		s0 := tt.s0
+		fillMissing(&s0, tt.known)
+		for r := range s0 {
+			if math.Abs(s0[r]-tt.want[r]) > 1e-9*tt.want[r] {
+				t.Errorf("%s: w[%d] = %v, want %v", tt.name, r, s0[r], tt.want[r])
+			}
+		}
+	}
+}
+
+func TestFillMissingInterpolatesInLogSpace(t *testing.T) {
+	d := flux.DefaultParameters
+	s0 := [4]float64{d[0], 0, 0, 4 * d[3]}
+	fillMissing(&s0, [4]bool{true, false, false, true})
+	if !(s0[0] < s0[1] && s0[1] < s0[2] && s0[2] < s0[3]) {
+		t.Errorf("interpolated stabilities not increasing: %v", s0)
+	}
+	if s0[1] <= d[1] || s0[2] <= d[2] {
+		t.Errorf("interpolation did not move toward the fitted end: %v", s0)
+	}
+}
+
+func TestPretrainClamps(t *testing.T) {
+	data := recallData(flux.Easy, 1000, 100, 100, 200)
+	params, ok := pretrain(data)
+	if !ok {
+		t.Fatal("pretrain reported no data")
+	}
+	for r := range 4 {
+		if params[r] > initStabilityMax || params[r] < flux.LowerBounds[r] {
+			t.Errorf("w[%d] = %v out of [%v, %v]", r, params[r], flux.LowerBounds[r], initStabilityMax)
+		}
+	}
+}
+
+func TestPretrainParameters(t *testing.T) {
+	o := NewOptimizer(OptimizerConfig{})
+
+	if _, err := o.PretrainParameters(nil); !errors.Is(err, ErrEmptyLogs) {
+		t.Errorf("empty logs: err = %v, want ErrEmptyLogs", err)
+	}
+
+	t0 := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	sameDay := []flux.ReviewLog{
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0},
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(time.Hour)},
+	}
+	params, err := o.PretrainParameters(sameDay)
+	if !errors.Is(err, ErrInsufficientData) || params != flux.DefaultParameters {
+		t.Errorf("same-day logs: got %v, %v; want DefaultParameters, ErrInsufficientData", params, err)
+	}
+
+	params, err = o.PretrainParameters(generateSyntheticLogs(200, 5, 42))
+	if err != nil {
+		t.Fatalf("PretrainParameters: %v", err)
+	}
+	if err := flux.ValidateParameters(params); err != nil {
+		t.Errorf("pretrained parameters invalid: %v", err)
+	}
+	for r := 1; r < 4; r++ {
+		if params[r] < params[r-1] {
+			t.Errorf("w[0..3] not monotonic: %v", params[:4])
+		}
+	}
+}
@@ -1,50 +1,77 @@
 package optimizer
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"math"
-	"math/rand"
-	"sort"
+	"slices"
 	"time"
 
 	"github.com/sky-flux/flux"
 )
 
 var (
-	// ErrInsufficientLogs is returned when fewer than 512 review logs are provided.
+	// ErrInsufficientLogs is returned when fewer than 512 trainable review
+	// logs are provided.
 	ErrInsufficientLogs = errors.New("optimizer: at least 512 review logs required for optimal retention")
 
-	// ErrMissingDuration is returned when any ReviewDuration is nil.
+	// ErrMissingDuration is returned when a trainable log's ReviewDuration
+	// is nil.
 	ErrMissingDuration = errors.New("optimizer: ReviewDuration must not be nil for optimal retention")
+
+	// ErrInvalidRetentionRange is returned when MinRetention and MaxRetention
+	// do not form a range within (0, 1).
+	ErrInvalidRetentionRange = errors.New("optimizer: invalid retention search range")
+
+	// ErrInvalidSimulationSize is returned when SimulatedCards or
+	// SimulationDays is negative.
+	ErrInvalidSimulationSize = errors.New("optimizer: invalid retention simulation size")
+
+	// ErrInvalidObjective is returned when Objective is not a known
+	// RetentionObjective.
+	ErrInvalidObjective = errors.New("optimizer: invalid retention objective")
+
+	// ErrNoFeasibleRetention is returned when no evaluated retention
+	// satisfies the RetentionConstraints.
+	ErrNoFeasibleRetention = errors.New("optimizer: no retention satisfies the constraints")
 )
 
+// RetentionPoint is the simulated outcome at one desired retention.
+type RetentionPoint struct {
+	Retention         float64 `json:"retention"`
+	Cost              float64 `json:"cost"`               // review time per memorized card, in milliseconds
+	Memorized         float64 `json:"memorized"`          // Σ retrievability of the simulated cards at the end
+	MemorizedFraction float64 `json:"memorized_fraction"` // Memorized as a share of the simulated cards
+	DailyMinutes      float64 `json:"daily_minutes"`      // average review time per simulated day
+}
+
+// RetentionCurve is the result of a retention search.
+type RetentionCurve struct {
+	Optimal RetentionPoint   `json:"optimal"` // the best point under the objective and constraints
+	Points  []RetentionPoint `json:"points"`  // every evaluated point, by ascending retention
+}
+
 // computeProbsAndCosts computes rating probabilities and average durations from review logs.
-// "First review" = the first review of each card_id. "Non-first" = all subsequent reviews.
+// "First review" = the first review of each card_id since its last reset.
+// "Non-first" = all subsequent reviews.
 // For non-first recall rating probabilities, compute among only recalled reviews (not Again).
 func computeProbsAndCosts(logs []flux.ReviewLog) map[string]float64 {
 	// Group by card and sort by time to identify first vs non-first.
 	type entry struct {
 		rating   flux.Rating
 		duration float64
-		time     time.Time
 	}
-	groups := make(map[int64][]entry)
-	for _, log := range logs {
-		d := 0.0
-		if log.ReviewDuration != nil {
-			d = float64(*log.ReviewDuration)
+	groups := make(map[cardKey][]entry)
+	for cardID, cardLogs := range groupRatedLogs(logs) {
+		for _, log := range cardLogs {
+			d := 0.0
+			if log.ReviewDuration != nil {
+				d = float64(*log.ReviewDuration)
+			}
+			groups[cardID] = append(groups[cardID], entry{rating: log.Rating, duration: d})
 		}
-		groups[log.CardID] = append(groups[log.CardID], entry{
-			rating:   log.Rating,
-			duration: d,
-			time:     log.ReviewDatetime,
-		})
-	}
-	for _, g := range groups {
-		sort.Slice(g, func(i, j int) bool {
-			return g[i].time.Before(g[j].time)
-		})
 	}
 
 	// Counters for first reviews.
@@ -125,24 +152,33 @@ var ratingLowerNames = map[flux.Rating]string{
 	flux.Easy:  "easy",
 }
 
-// simulateCost runs a Monte Carlo simulation to estimate the cost per retained card
-// for a given desired retention. It simulates 1000 cards over one year.
-func simulateCost(retention float64, params [21]float64, probsAndCosts map[string]float64) float64 {
-	const numCards = 1000
+// simulationSize sets the scale of the Monte Carlo simulation behind the
+// retention search.
+type simulationSize struct {
+	cards   int // cards simulated, all new on the first day
+	days    int // length of the simulation
+	workers int // goroutines the cards are spread over
+}
 
+// simulateRetention runs a Monte Carlo simulation of size.cards cards over
+// size.days days at the given desired retention. Invalid params, or a
+// simulation that memorizes nothing, yield infinite cost and workload.
+//
+// Cards are simulated in parallel, each from its own random stream, and
+// their results are summed in card order, so the outcome is identical for
+// any worker count.
+func simulateRetention(retention float64, params [21]float64, probsAndCosts map[string]float64, size simulationSize) RetentionPoint {
 	s, err := flux.NewScheduler(flux.SchedulerConfig{
 		Parameters:       params,
 		DesiredRetention: retention,
 		DisableFuzzing:   true,
 	})
 	if err != nil {
-		return math.Inf(1)
+		return RetentionPoint{Retention: retention, Cost: math.Inf(1), DailyMinutes: math.Inf(1)}
 	}
 
-	rng := rand.New(rand.NewSource(42))
-
 	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.Add(time.Duration(size.days) * 24 * time.Hour)
 
 	// Extract probabilities and costs.
 	pfAgain := probsAndCosts["prob_first_again"]
@@ -164,11 +200,12 @@ func simulateCost(retention float64, params [21]float64, probsAndCosts map[strin
 	dGood := probsAndCosts["avg_good_duration"]
 	dEasy := probsAndCosts["avg_easy_duration"]
 
-	var totalDuration float64
+	durations := make([]float64, size.cards)
+	retained := make([]float64, size.cards)
 
-	for i := 0; i < numCards; i++ {
-		card := flux.NewCard(int64(i + 1))
-		card.Due = startDate
+	parallelFor(size.cards, size.workers, func(i int) {
+		rng := newCardStream(42, i)
+		card := flux.NewCardAt(int64(i+1), startDate)
 		now := startDate
 		isFirst := true
 
@@ -216,43 +253,200 @@ func simulateCost(retention float64, params [21]float64, probsAndCosts map[strin
 				}
 			}
 
-			totalDuration += dur
+			durations[i] += dur
 			card, _ = s.ReviewCard(card, rating, now)
 			now = card.Due
 		}
+		retained[i] = s.Retrievability(card, endDate)
+	})
+
+	var totalDuration, memorized float64
+	for i := range size.cards {
+		totalDuration += durations[i]
+		memorized += retained[i]
 	}
+	if memorized == 0 {
+		return RetentionPoint{Retention: retention, Cost: math.Inf(1), DailyMinutes: math.Inf(1)}
+	}
+	return RetentionPoint{
+		Retention:         retention,
+		Cost:              totalDuration / memorized,
+		Memorized:         memorized,
+		MemorizedFraction: memorized / float64(size.cards),
+		DailyMinutes:      totalDuration / 60000 / float64(size.days),
+	}
+}
 
-	return totalDuration / (retention * numCards)
+// cardStream is a splitmix64 stream of uniform values for one simulated
+// card. Each card draws from its own stream, keyed by its index, so every
+// candidate retention sees the same draws card by card (common random
+// numbers): cost differences between candidates reflect the retention, not
+// the sampling noise.
+type cardStream struct {
+	x uint64
 }
 
-// ComputeOptimalRetention finds the retention value (from candidates) with minimal
-// simulated cost. It validates inputs and checks for context cancellation.
+func newCardStream(seed int64, card int) *cardStream {
+	return &cardStream{x: uint64(seed)<<32 ^ uint64(card)*0xd1b54a32d192ed03}
+}
+
+// Float64 returns the next value in [0, 1).
+func (s *cardStream) Float64() float64 {
+	s.x += 0x9e3779b97f4a7c15
+	z := s.x
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return float64(z>>11) / (1 << 53)
+}
+
+// ComputeOptimalRetention returns the best desired retention under the
+// configured objective and constraints; by default, the one with the lowest
+// simulated review time per memorized card. It is ComputeRetentionCurve
+// without the curve.
 func (o *Optimizer) ComputeOptimalRetention(ctx context.Context, params [21]float64, logs []flux.ReviewLog) (float64, error) {
-	if len(logs) < 512 {
-		return 0, ErrInsufficientLogs
+	curve, err := o.ComputeRetentionCurve(ctx, params, logs)
+	if err != nil {
+		return 0, err
 	}
+	return curve.Optimal.Retention, nil
+}
+
+// ComputeRetentionCurve searches [MinRetention, MaxRetention] for the best
+// desired retention under the configured Objective and Constraints, and
+// returns every point it evaluated along with the optimum. The simulation is
+// driven by the rating probabilities and review durations in logs.
+//
+// The search evaluates an even grid of retentionGridPoints over the range,
+// then narrows in on the best grid point by golden-section search to within
+// retentionTolerance. Every candidate is simulated with the same random
+// draws, so the noisy costs are directly comparable. The grid candidates,
+// and the cards within each simulation, run in parallel on up to Workers
+// goroutines; results are identical for any Workers value.
+//
+// Returns ErrInsufficientLogs, ErrMissingDuration, ErrInvalidRetentionRange,
+// ErrInvalidSimulationSize or ErrInvalidObjective for unusable input, or the context's error if it is
+// canceled. If no evaluated retention meets the constraints, the curve is
+// returned with ErrNoFeasibleRetention.
+func (o *Optimizer) ComputeRetentionCurve(ctx context.Context, params [21]float64, logs []flux.ReviewLog) (RetentionCurve, error) {
+	// Only trainable logs drive the simulation; manual reschedules, resets
+	// and cram reviews carry no review time of their own.
+	n := 0
 	for _, log := range logs {
+		if !trainable(log.Kind) {
+			continue
+		}
 		if log.ReviewDuration == nil {
-			return 0, ErrMissingDuration
+			return RetentionCurve{}, ErrMissingDuration
 		}
+		n++
+	}
+	if n < 512 {
+		return RetentionCurve{}, ErrInsufficientLogs
+	}
+	lo, hi := o.minRetention, o.maxRetention
+	if !(lo > 0 && lo < hi && hi < 1) {
+		return RetentionCurve{}, fmt.Errorf("%w: [%v, %v]", ErrInvalidRetentionRange, lo, hi)
+	}
+	if o.simulatedCards <= 0 || o.simulationDays <= 0 {
+		return RetentionCurve{}, fmt.Errorf("%w: %d cards over %d days", ErrInvalidSimulationSize, o.simulatedCards, o.simulationDays)
+	}
+	if !o.objective.isValid() {
+		return RetentionCurve{}, fmt.Errorf("%w: %v", ErrInvalidObjective, o.objective)
 	}
 
 	probsAndCosts := computeProbsAndCosts(logs)
-	candidates := []float64{0.70, 0.75, 0.80, 0.85, 0.90, 0.95}
+	curve, err := searchRetention(ctx, lo, hi, o.workers,
+		func(r float64, workers int) RetentionPoint {
+			size := simulationSize{cards: o.simulatedCards, days: o.simulationDays, workers: workers}
+			return simulateRetention(r, params, probsAndCosts, size)
+		},
+		func(p RetentionPoint) float64 {
+			return score(o.objective, o.constraints, p)
+		})
+	if err != nil {
+		return RetentionCurve{}, err
+	}
+	if !o.constraints.feasible(curve.Optimal) {
+		return curve, ErrNoFeasibleRetention
+	}
+	return curve, nil
+}
 
-	bestRetention := candidates[0]
-	bestCost := math.Inf(1)
+// Retention search settings: the grid that brackets the optimum and the
+// width at which golden-section search stops.
+const (
+	retentionGridPoints = 6
+	retentionTolerance  = 0.001
+)
 
-	for _, c := range candidates {
+// searchRetention minimizes score(simulate(r)) over r in [lo, hi]: a grid
+// search finds the best bracket, which golden-section search then narrows.
+// simulate is passed its share of the workers goroutines: the grid's
+// simulations run concurrently and split them, while each golden-section
+// step runs alone and gets them all, so at most workers are busy at once.
+// The optimum is the lowest-scoring point seen; ties go to the lower
+// retention.
+func searchRetention(ctx context.Context, lo, hi float64, workers int, simulate func(r float64, workers int) RetentionPoint, score func(RetentionPoint) float64) (RetentionCurve, error) {
+	if err := ctx.Err(); err != nil {
+		return RetentionCurve{}, err
+	}
+	step := (hi - lo) / (retentionGridPoints - 1)
+	points := make([]RetentionPoint, retentionGridPoints)
+	gridWorkers := max(min(workers, retentionGridPoints), 1)
+	parallelFor(retentionGridPoints, gridWorkers, func(i int) {
+		points[i] = simulate(lo+float64(i)*step, max(workers/gridWorkers, 1))
+	})
+	best, bestScore := points[0].Retention, score(points[0])
+	for _, p := range points[1:] {
+		if f := score(p); f < bestScore {
+			best, bestScore = p.Retention, f
+		}
+	}
+
+	eval := func(r float64) (float64, error) {
 		if err := ctx.Err(); err != nil {
 			return 0, err
 		}
-		cost := simulateCost(c, params, probsAndCosts)
-		if cost < bestCost {
-			bestCost = cost
-			bestRetention = c
+		p := simulate(r, workers)
+		points = append(points, p)
+		return score(p), nil
+	}
+
+	invPhi := (math.Sqrt(5) - 1) / 2
+	a, b := max(lo, best-step), min(hi, best+step)
+	x1, x2 := b-invPhi*(b-a), a+invPhi*(b-a)
+	f1, err := eval(x1)
+	if err != nil {
+		return RetentionCurve{}, err
+	}
+	f2, err := eval(x2)
+	if err != nil {
+		return RetentionCurve{}, err
+	}
+	for b-a > retentionTolerance {
+		if f1 < f2 {
+			b, x2, f2 = x2, x1, f1
+			x1 = b - invPhi*(b-a)
+			f1, err = eval(x1)
+		} else {
+			a, x1, f1 = x1, x2, f2
+			x2 = a + invPhi*(b-a)
+			f2, err = eval(x2)
+		}
+		if err != nil {
+			return RetentionCurve{}, err
 		}
 	}
 
-	return bestRetention, nil
+	slices.SortFunc(points, func(p, q RetentionPoint) int {
+		return cmp.Compare(p.Retention, q.Retention)
+	})
+	optimal := points[0]
+	for _, p := range points[1:] {
+		if score(p) < score(optimal) {
+			optimal = p
+		}
+	}
+	return RetentionCurve{Optimal: optimal, Points: points}, nil
 }
@@ -1,9 +1,13 @@
 package optimizer
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"math"
+	"reflect"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -81,39 +85,71 @@ func TestComputeProbsAndCostsFirstOnly(t *testing.T) {
 	assertFloatOpt(t, "prob_easy", m["prob_easy"], 1.0/3.0)
 }
 
-// --- simulateCost ---
+func TestComputeProbsAndCostsAfterReset(t *testing.T) {
+	dur := func(ms int) *int { return &ms }
+
+	// The first review after the reset counts as the card's first review.
+	logs := []flux.ReviewLog{
+		{CardID: 1, Rating: flux.Again, ReviewDatetime: t0, ReviewDuration: dur(900)},
+		{CardID: 1, Kind: flux.KindReset, ReviewDatetime: t0.Add(24 * time.Hour)},
+		{CardID: 1, Rating: flux.Good, ReviewDatetime: t0.Add(48 * time.Hour), ReviewDuration: dur(300)},
+	}
+
+	m := computeProbsAndCosts(logs)
+	assertFloatOpt(t, "prob_first_good", m["prob_first_good"], 1)
+	assertFloatOpt(t, "avg_first_good_duration", m["avg_first_good_duration"], 300)
+	if _, ok := m["avg_first_again_duration"]; ok {
+		t.Error("review before the reset should not count")
+	}
+}
+
+// --- simulateRetention ---
 
-func TestSimulateCostInvalidParams(t *testing.T) {
+func TestSimulateRetentionInvalidParams(t *testing.T) {
 	// Non-zero but out-of-bounds params → NewScheduler fails → +Inf.
 	// w[4] lower bound is 1.0; set to 0.5 to trigger validation error.
 	badParams := flux.DefaultParameters
 	badParams[4] = 0.5
 	m := defaultProbsAndCosts()
-	cost := simulateCost(0.9, badParams, m)
-	if !math.IsInf(cost, 1) {
-		t.Errorf("simulateCost with invalid params = %f, want +Inf", cost)
+	p := simulateRetention(0.9, badParams, m, testSize)
+	if !math.IsInf(p.Cost, 1) || !math.IsInf(p.DailyMinutes, 1) || p.Memorized != 0 {
+		t.Errorf("simulateRetention with invalid params = %+v, want infinite cost and workload", p)
+	}
+}
+
+func TestSimulateRetentionNothingMemorized(t *testing.T) {
+	p := simulateRetention(0.9, flux.DefaultParameters, defaultProbsAndCosts(), simulationSize{days: 30, workers: 1})
+	if !math.IsInf(p.Cost, 1) || !math.IsInf(p.DailyMinutes, 1) || p.Memorized != 0 {
+		t.Errorf("simulateRetention with no cards = %+v, want infinite cost and workload", p)
 	}
 }
 
-func TestSimulateCostReproducible(t *testing.T) {
+func TestSimulateRetentionReproducible(t *testing.T) {
 	m := defaultProbsAndCosts()
-	cost1 := simulateCost(0.9, flux.DefaultParameters, m)
-	cost2 := simulateCost(0.9, flux.DefaultParameters, m)
-	if cost1 != cost2 {
-		t.Errorf("simulateCost not reproducible: %f != %f", cost1, cost2)
+	p1 := simulateRetention(0.9, flux.DefaultParameters, m, testSize)
+	p2 := simulateRetention(0.9, flux.DefaultParameters, m, testSize)
+	if p1 != p2 {
+		t.Errorf("simulateRetention not reproducible: %+v != %+v", p1, p2)
 	}
-	if cost1 <= 0 {
-		t.Errorf("simulateCost = %f, want > 0", cost1)
+	if p1.Cost <= 0 || p1.Memorized <= 0 || p1.Memorized > 1000 || p1.DailyMinutes <= 0 {
+		t.Errorf("simulateRetention = %+v", p1)
+	}
+	if p1.MemorizedFraction != p1.Memorized/float64(testSize.cards) {
+		t.Errorf("MemorizedFraction = %v, want Memorized/%d", p1.MemorizedFraction, testSize.cards)
 	}
 }
 
-func TestSimulateCostHigherRetentionLowerCost(t *testing.T) {
+func TestSimulateRetentionHigherRetention(t *testing.T) {
 	m := defaultProbsAndCosts()
-	costLow := simulateCost(0.70, flux.DefaultParameters, m)
-	costHigh := simulateCost(0.95, flux.DefaultParameters, m)
-	// Higher retention → fewer lapses → generally lower cost per retained card.
-	if costHigh >= costLow {
-		t.Errorf("expected cost at 0.95 (%f) < cost at 0.70 (%f)", costHigh, costLow)
+	low := simulateRetention(0.70, flux.DefaultParameters, m, testSize)
+	high := simulateRetention(0.95, flux.DefaultParameters, m, testSize)
+	// Higher retention → fewer lapses → lower cost per memorized card, but
+	// more reviews and more cards remembered.
+	if high.Cost >= low.Cost {
+		t.Errorf("expected cost at 0.95 (%f) < cost at 0.70 (%f)", high.Cost, low.Cost)
+	}
+	if high.Memorized <= low.Memorized || high.DailyMinutes <= low.DailyMinutes {
+		t.Errorf("at 0.95 memorized, minutes = %v, %v; at 0.70 = %v, %v", high.Memorized, high.DailyMinutes, low.Memorized, low.DailyMinutes)
 	}
 }
 
@@ -158,6 +194,45 @@ func TestComputeOptimalRetentionMissingDuration(t *testing.T) {
 	}
 }
 
+func TestComputeOptimalRetentionManualLogs(t *testing.T) {
+	// SetDueDate logs carry no duration and do not count toward the minimum.
+	o := NewOptimizer(OptimizerConfig{})
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	due := t0.Add(30 * 24 * time.Hour)
+	logs = append(logs, flux.ReviewLog{CardID: 1, ReviewDatetime: t0.Add(time.Hour), Kind: flux.KindManual, Due: &due})
+	if _, err := o.ComputeOptimalRetention(context.Background(), flux.DefaultParameters, logs); err != nil {
+		t.Errorf("with a manual log: %v", err)
+	}
+
+	dur := 1000
+	few := make([]flux.ReviewLog, 600)
+	for i := range few {
+		few[i] = flux.ReviewLog{CardID: int64(i + 1), ReviewDatetime: t0, ReviewDuration: &dur, Kind: flux.KindManual, Due: &due}
+	}
+	few[0].Rating, few[0].Kind, few[0].Due = flux.Good, flux.KindRated, nil
+	if _, err := o.ComputeOptimalRetention(context.Background(), flux.DefaultParameters, few); !errors.Is(err, ErrInsufficientLogs) {
+		t.Errorf("one rated log among manual ones: error = %v, want ErrInsufficientLogs", err)
+	}
+}
+
+func TestComputeOptimalRetentionResetLogs(t *testing.T) {
+	// ResetCard logs carry no duration; the reset card's earlier reviews
+	// are dropped and the search still runs.
+	o := NewOptimizer(OptimizerConfig{})
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	logs = append(logs,
+		flux.ReviewLog{CardID: 1, ReviewDatetime: t0.Add(400 * 24 * time.Hour), Kind: flux.KindReset},
+		flux.ReviewLog{CardID: 2, ReviewDatetime: t0.Add(400 * 24 * time.Hour), Kind: flux.KindReset, ResetCounts: true},
+	)
+	ret, err := o.ComputeOptimalRetention(context.Background(), flux.DefaultParameters, logs)
+	if err != nil {
+		t.Fatalf("with reset logs: %v", err)
+	}
+	if ret < 0.70 || ret > 0.95 {
+		t.Errorf("retention = %f, want within [0.70, 0.95]", ret)
+	}
+}
+
 func TestComputeOptimalRetentionValid(t *testing.T) {
 	o := NewOptimizer(OptimizerConfig{})
 	logs := generateSyntheticLogsWithDuration(200, 10, 42)
@@ -166,16 +241,142 @@ func TestComputeOptimalRetentionValid(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ComputeOptimalRetention: %v", err)
 	}
-	// Result must be one of the candidates.
-	valid := false
-	for _, c := range []float64{0.70, 0.75, 0.80, 0.85, 0.90, 0.95} {
-		if ret == c {
-			valid = true
-			break
+	if ret < 0.70 || ret > 0.95 {
+		t.Errorf("retention = %f, want within [0.70, 0.95]", ret)
+	}
+}
+
+func TestComputeRetentionCurve(t *testing.T) {
+	o := NewOptimizer(OptimizerConfig{})
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+
+	curve, err := o.ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs)
+	if err != nil {
+		t.Fatalf("ComputeRetentionCurve: %v", err)
+	}
+	if len(curve.Points) <= retentionGridPoints {
+		t.Fatalf("curve has %d points, want the grid plus refinement", len(curve.Points))
+	}
+	if !slices.IsSortedFunc(curve.Points, func(p, q RetentionPoint) int { return cmp.Compare(p.Retention, q.Retention) }) {
+		t.Error("curve points are not sorted by retention")
+	}
+	for _, p := range curve.Points {
+		if p.Cost < curve.Optimal.Cost {
+			t.Errorf("point %+v is cheaper than the optimum %+v", p, curve.Optimal)
+		}
+	}
+	ret, _ := o.ComputeOptimalRetention(context.Background(), flux.DefaultParameters, logs)
+	if ret != curve.Optimal.Retention {
+		t.Errorf("ComputeOptimalRetention = %v, curve optimum = %v", ret, curve.Optimal.Retention)
+	}
+}
+
+func TestComputeRetentionCurveRange(t *testing.T) {
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	o := NewOptimizer(OptimizerConfig{MinRetention: 0.82, MaxRetention: 0.86})
+	curve, err := o.ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs)
+	if err != nil {
+		t.Fatalf("ComputeRetentionCurve: %v", err)
+	}
+	if first, last := curve.Points[0].Retention, curve.Points[len(curve.Points)-1].Retention; first != 0.82 || math.Abs(last-0.86) > 1e-12 {
+		t.Errorf("curve spans [%v, %v], want [0.82, 0.86]", first, last)
+	}
+
+	for _, r := range [][2]float64{{0.9, 0.8}, {0.5, 1}, {-0.1, 0.5}} {
+		o := NewOptimizer(OptimizerConfig{MinRetention: r[0], MaxRetention: r[1]})
+		if _, err := o.ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs); !errors.Is(err, ErrInvalidRetentionRange) {
+			t.Errorf("range %v: error = %v, want ErrInvalidRetentionRange", r, err)
+		}
+	}
+}
+
+func TestComputeRetentionCurveSize(t *testing.T) {
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	for _, cfg := range []OptimizerConfig{{SimulatedCards: -1}, {SimulationDays: -30}} {
+		o := NewOptimizer(cfg)
+		if _, err := o.ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs); !errors.Is(err, ErrInvalidSimulationSize) {
+			t.Errorf("%d cards over %d days: error = %v, want ErrInvalidSimulationSize", cfg.SimulatedCards, cfg.SimulationDays, err)
+		}
+	}
+}
+
+func TestSearchRetentionContinuous(t *testing.T) {
+	// A smooth cost with its minimum between grid points.
+	curve, err := searchRetention(context.Background(), 0.70, 0.95, 1, costCurve(func(r float64) float64 {
+		return (r - 0.883) * (r - 0.883)
+	}), byCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(curve.Optimal.Retention-0.883) > retentionTolerance {
+		t.Errorf("optimum = %v, want 0.883 ± %v", curve.Optimal.Retention, retentionTolerance)
+	}
+}
+
+func TestSearchRetentionWorkerBudget(t *testing.T) {
+	// Simulations running at once never hold more than workers goroutines
+	// between them, and golden-section steps get the whole budget.
+	for _, workers := range []int{1, 4, 6, 8, 20} {
+		var mu sync.Mutex
+		busy, peak, full := 0, 0, 0
+		simulate := func(r float64, w int) RetentionPoint {
+			mu.Lock()
+			busy += w
+			peak = max(peak, busy)
+			if w == workers {
+				full++
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			busy -= w
+			mu.Unlock()
+			return RetentionPoint{Retention: r, Cost: -r}
+		}
+		if _, err := searchRetention(context.Background(), 0.70, 0.95, workers, simulate, byCost); err != nil {
+			t.Fatal(err)
 		}
+		if peak > workers {
+			t.Errorf("workers=%d: %d goroutines busy at once", workers, peak)
+		}
+		if full == 0 {
+			t.Errorf("workers=%d: no simulation got the whole budget", workers)
+		}
+	}
+}
+
+func TestSearchRetentionInfiniteCost(t *testing.T) {
+	curve, err := searchRetention(context.Background(), 0.70, 0.95, 1, costCurve(func(float64) float64 { return math.Inf(1) }), byCost)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if !valid {
-		t.Errorf("retention = %f, want one of [0.70, 0.75, 0.80, 0.85, 0.90, 0.95]", ret)
+	if curve.Optimal.Retention != 0.70 {
+		t.Errorf("optimum = %v, want the lower bound when every cost is +Inf", curve.Optimal.Retention)
+	}
+}
+
+func TestSearchRetentionCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := searchRetention(ctx, 0.70, 0.95, 1, costCurve(func(r float64) float64 { return -r }), byCost); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled before the grid: error = %v, want context.Canceled", err)
+	}
+
+	// Cancel so that the next evaluation falls in each stage after the grid:
+	// both initial golden-section points and a refinement step.
+	for _, after := range []int{retentionGridPoints, retentionGridPoints + 1, retentionGridPoints + 2} {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, err := searchRetention(ctx, 0.70, 0.95, 1, costCurve(func(r float64) float64 {
+			if calls++; calls == after {
+				cancel()
+			}
+			return -r
+		}), byCost)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("cancel after %d calls: error = %v, want context.Canceled", after, err)
+		}
+		cancel()
 	}
 }
 
@@ -192,8 +393,50 @@ func TestComputeOptimalRetentionContextCancel(t *testing.T) {
 	}
 }
 
+func TestComputeRetentionCurveWorkersIdentical(t *testing.T) {
+	logs := generateSyntheticLogsWithDuration(200, 10, 42)
+	curve := func(workers int) RetentionCurve {
+		o := NewOptimizer(OptimizerConfig{Workers: workers, SimulatedCards: 300, SimulationDays: 120})
+		c, err := o.ComputeRetentionCurve(context.Background(), flux.DefaultParameters, logs)
+		if err != nil {
+			t.Fatalf("workers=%d: %v", workers, err)
+		}
+		return c
+	}
+	want := curve(1)
+	for _, workers := range []int{2, 7} {
+		if got := curve(workers); !reflect.DeepEqual(got, want) {
+			t.Errorf("workers=%d: curve differs from workers=1", workers)
+		}
+	}
+}
+
+func TestSimulateRetentionSize(t *testing.T) {
+	m := defaultProbsAndCosts()
+	p := simulateRetention(0.9, flux.DefaultParameters, m, simulationSize{cards: 50, days: 30, workers: 2})
+	if p.Memorized <= 0 || p.Memorized > 50 {
+		t.Errorf("memorized = %v, want within (0, 50]", p.Memorized)
+	}
+	long := simulateRetention(0.9, flux.DefaultParameters, m, simulationSize{cards: 50, days: 300, workers: 2})
+	if long.DailyMinutes >= p.DailyMinutes {
+		t.Errorf("a longer horizon should spread the first-day load: 30 days %+v, 300 days %+v", p, long)
+	}
+}
+
 // --- helpers ---
 
+// testSize is the default simulation size, on several workers.
+var testSize = simulationSize{cards: 1000, days: 365, workers: 4}
+
+// costCurve adapts a cost function of retention to searchRetention.
+func costCurve(cost func(r float64) float64) func(r float64, workers int) RetentionPoint {
+	return func(r float64, _ int) RetentionPoint {
+		return RetentionPoint{Retention: r, Cost: cost(r)}
+	}
+}
+
+func byCost(p RetentionPoint) float64 { return p.Cost }
+
 // defaultProbsAndCosts returns a reasonable probsAndCosts map for testing.
 func defaultProbsAndCosts() map[string]float64 {
 	return map[string]float64{
@@ -0,0 +1,56 @@
+package flux
+
+import "time"
+
+// SuspendCard removes the card from rotation until UnsuspendCard.
+// Its scheduling state is kept intact. The input card is not mutated.
+func (s *Scheduler) SuspendCard(card Card) Card {
+	c := card.clone()
+	c.Queue, c.BuriedUntil = QueueSuspended, nil
+	return c
+}
+
+// UnsuspendCard returns a suspended or buried card to rotation with the
+// scheduling state it had before. The input card is not mutated.
+func (s *Scheduler) UnsuspendCard(card Card) Card {
+	c := card.clone()
+	c.Queue, c.BuriedUntil = QueueActive, nil
+	return c
+}
+
+// BuryCard hides the card until the start of the next day: the learner's
+// next day when a Location is set, the next midnight in now's location
+// otherwise. Burying a suspended card leaves it suspended.
+// The input card is not mutated.
+func (s *Scheduler) BuryCard(card Card, now time.Time) Card {
+	c := card.clone()
+	if c.Queue == QueueSuspended {
+		return c
+	}
+	until := s.nextDayStart(now)
+	c.Queue, c.BuriedUntil = QueueBuried, &until
+	return c
+}
+
+// IsDue reports whether the card should be shown at now. Suspended cards
+// are never due; buried cards are not due before their BuriedUntil.
+func (s *Scheduler) IsDue(card Card, now time.Time) bool {
+	switch card.Queue {
+	case QueueSuspended:
+		return false
+	case QueueBuried:
+		if card.BuriedUntil != nil && now.Before(*card.BuriedUntil) {
+			return false
+		}
+	}
+	return !now.Before(card.Due)
+}
+
+// nextDayStart returns the start of the day after the one containing now.
+func (s *Scheduler) nextDayStart(now time.Time) time.Time {
+	if s.location == nil {
+		y, m, d := now.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, now.Location())
+	}
+	return s.dueAt(now, 1)
+}
@@ -0,0 +1,163 @@
+package flux
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// --- Queue ---
+
+func TestQueueString(t *testing.T) {
+	tests := []struct {
+		q    Queue
+		want string
+	}{
+		{QueueActive, "Active"},
+		{QueueSuspended, "Suspended"},
+		{QueueBuried, "Buried"},
+		{Queue(-1), "Queue(-1)"},
+		{Queue(3), "Queue(3)"},
+	}
+	for _, tt := range tests {
+		if got := tt.q.String(); got != tt.want {
+			t.Errorf("Queue(%d).String() = %q, want %q", int(tt.q), got, tt.want)
+		}
+	}
+}
+
+func TestQueueJSONRoundTrip(t *testing.T) {
+	for _, q := range []Queue{QueueActive, QueueSuspended, QueueBuried} {
+		data, err := json.Marshal(q)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", q, err)
+		}
+		if string(data) != `"`+q.String()+`"` {
+			t.Errorf("Marshal(%v) = %s", q, data)
+		}
+		var got Queue
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != q {
+			t.Errorf("round-trip: got %v, want %v", got, q)
+		}
+	}
+}
+
+func TestQueueMarshalJSONInvalid(t *testing.T) {
+	if _, err := json.Marshal(Queue(7)); err == nil {
+		t.Error("json.Marshal(Queue(7)) should return error")
+	}
+}
+
+func TestQueueUnmarshalJSONInvalid(t *testing.T) {
+	for _, input := range []string{`"Unknown"`, `""`, `1`} {
+		var q Queue
+		if err := json.Unmarshal([]byte(input), &q); err == nil {
+			t.Errorf("json.Unmarshal(%s) should return error", input)
+		}
+	}
+}
+
+func TestCardJSONQueue(t *testing.T) {
+	until := t0.Add(time.Hour)
+	c := reviewCard(t)
+	c.Queue, c.BuriedUntil = QueueBuried, &until
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got Card
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Queue != QueueBuried || !got.BuriedUntil.Equal(until) {
+		t.Errorf("round-trip Queue, BuriedUntil = %v, %v, want Buried, %v", got.Queue, got.BuriedUntil, until)
+	}
+}
+
+// --- Scheduler ---
+
+func TestSuspendUnsuspendRestoresState(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	orig := reviewCard(t)
+	susp := s.SuspendCard(orig)
+	if susp.Queue != QueueSuspended || orig.Queue != QueueActive {
+		t.Fatalf("SuspendCard: Queue = %v (input %v), want Suspended (Active)", susp.Queue, orig.Queue)
+	}
+	if s.IsDue(susp, t0.Add(365*24*time.Hour)) {
+		t.Error("suspended card should never be due")
+	}
+
+	back := s.UnsuspendCard(susp)
+	if back.Queue != QueueActive || back.State != orig.State || !back.Due.Equal(orig.Due) ||
+		*back.Stability != *orig.Stability || *back.Difficulty != *orig.Difficulty {
+		t.Errorf("UnsuspendCard = %+v, want %+v", back, orig)
+	}
+	if !s.IsDue(back, orig.Due) {
+		t.Error("unsuspended card should be due at its Due")
+	}
+}
+
+func TestBuryCard(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	now := t0.Add(2 * time.Hour)
+	c := s.BuryCard(reviewCard(t), now)
+	midnight := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	if c.Queue != QueueBuried || !c.BuriedUntil.Equal(midnight) {
+		t.Fatalf("BuryCard: Queue, BuriedUntil = %v, %v, want Buried, %v", c.Queue, c.BuriedUntil, midnight)
+	}
+	if s.IsDue(c, midnight.Add(-time.Second)) {
+		t.Error("buried card due before the next day")
+	}
+	if !s.IsDue(c, midnight) {
+		t.Error("buried card should be due again at the next day")
+	}
+}
+
+func TestBuryCardDayBoundary(t *testing.T) {
+	s := mustScheduler(t, dayCfg())
+	now := time.Date(2025, 6, 16, 2, 0, 0, 0, est) // still the learner's 15th
+	c := s.BuryCard(reviewCard(t), now)
+	if want := time.Date(2025, 6, 16, 4, 0, 0, 0, est); !c.BuriedUntil.Equal(want) {
+		t.Errorf("BuriedUntil = %v, want %v", c.BuriedUntil, want)
+	}
+}
+
+func TestBuryCardSuspended(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := s.BuryCard(s.SuspendCard(reviewCard(t)), t0)
+	if c.Queue != QueueSuspended || c.BuriedUntil != nil {
+		t.Errorf("BuryCard on suspended card: Queue, BuriedUntil = %v, %v", c.Queue, c.BuriedUntil)
+	}
+}
+
+func TestIsDueBuriedWithoutUntil(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := reviewCard(t)
+	c.Queue = QueueBuried
+	if !s.IsDue(c, t0) {
+		t.Error("buried card without BuriedUntil should fall back to Due")
+	}
+	if s.IsDue(c, t0.Add(-time.Second)) {
+		t.Error("card not yet due")
+	}
+}
+
+func TestReviewCardUnburies(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := s.BuryCard(reviewCard(t), t0)
+	c, _ = s.ReviewCard(c, Good, t0.Add(time.Hour))
+	if c.Queue != QueueActive || c.BuriedUntil != nil {
+		t.Errorf("after review: Queue, BuriedUntil = %v, %v, want Active, nil", c.Queue, c.BuriedUntil)
+	}
+}
+
+func TestReviewCardKeepsSuspension(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c, _ := s.ReviewCard(s.SuspendCard(reviewCard(t)), Good, t0.Add(5*24*time.Hour))
+	if c.Queue != QueueSuspended {
+		t.Errorf("Queue = %v, want Suspended", c.Queue)
+	}
+}
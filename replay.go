@@ -0,0 +1,116 @@
+package flux
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ReplayOptions configures RescheduleCardWith.
+type ReplayOptions struct {
+	// Lenient repairs the logs instead of rejecting them: logs with an
+	// invalid rating or a future date are dropped, and the rest are sorted
+	// by time and deduplicated. zero → strict: the first problem is an error.
+	Lenient bool
+	// Now is the reference time for future-dated logs.
+	// zero → the Scheduler's clock.
+	Now time.Time
+}
+
+// ReplayReport says what RescheduleCardWith repaired in lenient mode.
+// A successful strict replay always returns the zero report.
+type ReplayReport struct {
+	Invalid    int  // Logs dropped for a missing or invalid rating.
+	Future     int  // Logs dropped for being dated after ReplayOptions.Now.
+	Sorted     bool // The logs were out of order and have been sorted.
+	Duplicates int  // Logs dropped for repeating the time and kind of another.
+}
+
+// RescheduleCardWith is RescheduleCard with sanity checks on the logs.
+//
+// Every rated log must carry a valid rating and no log may be dated after
+// opts.Now. Timestamps must not decrease, and no two logs of the same kind
+// may share one; a reset and a review at the same instant are fine.
+// In strict mode the checks run in that order and the first failure is
+// returned wrapping ErrInvalidRating, ErrFutureLog, ErrLogOutOfOrder or
+// ErrDuplicateLog. In lenient mode the logs are repaired instead, and the
+// report counts what was changed. A log for another card is ErrCardIDMismatch in
+// both modes. The caller's slice is never modified.
+func (s *Scheduler) RescheduleCardWith(card Card, logs []ReviewLog, opts ReplayOptions) (Card, ReplayReport, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = s.clock.Now()
+	}
+
+	var report ReplayReport
+	valid := make([]ReviewLog, 0, len(logs))
+	for i, log := range logs {
+		if err := card.matches(log); err != nil {
+			return Card{}, ReplayReport{}, err
+		}
+		var err error
+		switch {
+		case log.Kind.rated() && !log.Rating.IsValid():
+			report.Invalid++
+			err = fmt.Errorf("%w: log %d: %v", ErrInvalidRating, i, log.Rating)
+		case log.ReviewDatetime.After(now):
+			report.Future++
+			err = fmt.Errorf("%w: log %d at %v", ErrFutureLog, i, log.ReviewDatetime)
+		}
+		if err != nil {
+			if !opts.Lenient {
+				return Card{}, ReplayReport{}, err
+			}
+			continue
+		}
+		valid = append(valid, log)
+	}
+
+	// In strict mode nothing has been dropped, so indices into valid are
+	// indices into logs.
+	if i := firstOutOfOrder(valid); i > 0 {
+		if !opts.Lenient {
+			return Card{}, ReplayReport{}, fmt.Errorf("%w: log %d at %v", ErrLogOutOfOrder, i, valid[i].ReviewDatetime)
+		}
+		slices.SortStableFunc(valid, func(a, b ReviewLog) int {
+			return a.ReviewDatetime.Compare(b.ReviewDatetime)
+		})
+		report.Sorted = true
+	}
+
+	kept := valid[:0]
+	for i, log := range valid {
+		if duplicateOf(kept, log) {
+			if !opts.Lenient {
+				return Card{}, ReplayReport{}, fmt.Errorf("%w: log %d repeats a %v log at %v", ErrDuplicateLog, i, log.Kind, log.ReviewDatetime)
+			}
+			report.Duplicates++
+			continue
+		}
+		kept = append(kept, log)
+	}
+
+	return s.replay(card, kept), report, nil
+}
+
+// firstOutOfOrder returns the index of the first log dated before its
+// predecessor, or 0 if the logs are in order.
+func firstOutOfOrder(logs []ReviewLog) int {
+	for i := 1; i < len(logs); i++ {
+		if logs[i].ReviewDatetime.Before(logs[i-1].ReviewDatetime) {
+			return i
+		}
+	}
+	return 0
+}
+
+// duplicateOf reports whether sorted logs already hold a log of the same
+// kind at the same instant as log. Equal instants sit at the end of logs.
+func duplicateOf(logs []ReviewLog, log ReviewLog) bool {
+	for i := len(logs) - 1; i >= 0 && logs[i].ReviewDatetime.Equal(log.ReviewDatetime); i-- {
+		if logs[i].Kind == log.Kind {
+			return true
+		}
+	}
+	return false
+}
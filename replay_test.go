@@ -0,0 +1,114 @@
+package flux
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// replayLogs is a clean three-review history of card 1.
+func replayLogs() []ReviewLog {
+	return []ReviewLog{
+		{CardID: 1, Rating: Good, ReviewDatetime: t0},
+		{CardID: 1, Rating: Good, ReviewDatetime: t0.Add(10 * time.Minute)},
+		{CardID: 1, Rating: Good, ReviewDatetime: t0.Add(5 * 24 * time.Hour)},
+	}
+}
+
+func TestRescheduleCardWithClean(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	want, _ := s.RescheduleCard(NewCard(1), replayLogs())
+	for _, lenient := range []bool{false, true} {
+		got, report, err := s.RescheduleCardWith(NewCard(1), replayLogs(), ReplayOptions{Lenient: lenient})
+		if err != nil {
+			t.Fatalf("lenient=%v: %v", lenient, err)
+		}
+		if report != (ReplayReport{}) {
+			t.Errorf("lenient=%v: report = %+v, want zero", lenient, report)
+		}
+		if !got.Due.Equal(want.Due) || got.Reps != want.Reps || *got.Stability != *want.Stability {
+			t.Errorf("lenient=%v: card = %+v, want %+v", lenient, got, want)
+		}
+	}
+}
+
+func TestRescheduleCardWithStrict(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	now := t0.Add(30 * 24 * time.Hour)
+	tests := []struct {
+		name   string
+		mutate func([]ReviewLog) []ReviewLog
+		want   error
+	}{
+		{"invalid rating", func(l []ReviewLog) []ReviewLog { l[1].Rating = 0; return l }, ErrInvalidRating},
+		{"future", func(l []ReviewLog) []ReviewLog { l[2].ReviewDatetime = now.Add(time.Hour); return l }, ErrFutureLog},
+		{"out of order", func(l []ReviewLog) []ReviewLog { l[0], l[1] = l[1], l[0]; return l }, ErrLogOutOfOrder},
+		{"duplicate", func(l []ReviewLog) []ReviewLog { return append(l[:2], l[1:]...) }, ErrDuplicateLog},
+		{"card ID", func(l []ReviewLog) []ReviewLog { l[2].CardID = 2; return l }, ErrCardIDMismatch},
+	}
+	for _, tt := range tests {
+		_, report, err := s.RescheduleCardWith(NewCard(1), tt.mutate(replayLogs()), ReplayOptions{Now: now})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+		if report != (ReplayReport{}) {
+			t.Errorf("%s: report = %+v, want zero", tt.name, report)
+		}
+	}
+}
+
+func TestRescheduleCardWithLenient(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	now := t0.Add(30 * 24 * time.Hour)
+	clean := replayLogs()
+	want, _ := s.RescheduleCard(NewCard(1), clean)
+
+	logs := []ReviewLog{
+		clean[2],
+		clean[0],
+		{CardID: 1, Rating: Rating(7), ReviewDatetime: t0.Add(time.Minute)},
+		clean[1],
+		clean[0],
+		{CardID: 1, Rating: Good, ReviewDatetime: now.Add(time.Hour)},
+		clean[2],
+	}
+	orig := append([]ReviewLog(nil), logs...)
+	got, report, err := s.RescheduleCardWith(NewCard(1), logs, ReplayOptions{Lenient: true, Now: now})
+	if err != nil {
+		t.Fatalf("RescheduleCardWith: %v", err)
+	}
+	wantReport := ReplayReport{Invalid: 1, Future: 1, Sorted: true, Duplicates: 2}
+	if report != wantReport {
+		t.Errorf("report = %+v, want %+v", report, wantReport)
+	}
+	if !got.Due.Equal(want.Due) || got.Reps != want.Reps || *got.Stability != *want.Stability {
+		t.Errorf("card = %+v, want %+v", got, want)
+	}
+	for i := range logs {
+		if !logs[i].ReviewDatetime.Equal(orig[i].ReviewDatetime) {
+			t.Fatal("RescheduleCardWith reordered the caller's logs")
+		}
+	}
+
+	_, _, err = s.RescheduleCardWith(NewCard(1), []ReviewLog{{CardID: 2, Rating: Good, ReviewDatetime: t0}}, ReplayOptions{Lenient: true})
+	if !errors.Is(err, ErrCardIDMismatch) {
+		t.Errorf("lenient card ID mismatch: error = %v, want ErrCardIDMismatch", err)
+	}
+}
+
+func TestRescheduleCardWithSameInstantKinds(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	logs := []ReviewLog{
+		{CardID: 1, Rating: Good, ReviewDatetime: t0},
+		{CardID: 1, Kind: KindReset, ReviewDatetime: t0},
+		{CardID: 1, Rating: Easy, ReviewDatetime: t0},
+	}
+	_, _, err := s.RescheduleCardWith(NewCard(1), logs[:2], ReplayOptions{})
+	if err != nil {
+		t.Errorf("review and reset at one instant: %v", err)
+	}
+	_, _, err = s.RescheduleCardWith(NewCard(1), logs, ReplayOptions{})
+	if !errors.Is(err, ErrDuplicateLog) {
+		t.Errorf("two reviews at one instant: error = %v, want ErrDuplicateLog", err)
+	}
+}
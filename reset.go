@@ -0,0 +1,36 @@
+package flux
+
+import "time"
+
+// ResetOptions configures ResetCard.
+type ResetOptions struct {
+	// ResetCounts also clears Reps, Lapses, FirstReview and the leech flag.
+	// By default they are kept, so the card's history stays visible.
+	ResetCounts bool
+}
+
+// ResetCard returns the card to new: Learning state at its first step, no
+// memory state, due at now and back in rotation. CardID and Key are kept. It
+// returns the reset card and a KindReset log, which RescheduleCard replays
+// and the optimizer treats as the start of a fresh history.
+// The input card is not mutated.
+func (s *Scheduler) ResetCard(card Card, now time.Time, opts ResetOptions) (Card, ReviewLog) {
+	c := card.clone()
+	c.State = Learning
+	c.setStep(0)
+	c.Stability, c.Difficulty, c.LastReview = nil, nil, nil
+	c.Due = now
+	c.Queue, c.BuriedUntil = QueueActive, nil
+	if opts.ResetCounts {
+		c.Reps, c.Lapses, c.FirstReview, c.Leech = 0, 0, nil, false
+	}
+
+	log := ReviewLog{
+		CardID:         c.CardID,
+		CardKey:        c.Key,
+		ReviewDatetime: now,
+		Kind:           KindReset,
+		ResetCounts:    opts.ResetCounts,
+	}
+	return c, log
+}
@@ -0,0 +1,170 @@
+package flux
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- ReviewKind ---
+
+func TestReviewKindString(t *testing.T) {
+	tests := []struct {
+		k    ReviewKind
+		want string
+	}{
+		{KindRated, "Rated"},
+		{KindLearn, "Learn"},
+		{KindReview, "Review"},
+		{KindRelearn, "Relearn"},
+		{KindFiltered, "Filtered"},
+		{KindManual, "Manual"},
+		{KindReset, "Reset"},
+		{ReviewKind(-1), "ReviewKind(-1)"},
+		{ReviewKind(99), "ReviewKind(99)"},
+	}
+	for _, tt := range tests {
+		if got := tt.k.String(); got != tt.want {
+			t.Errorf("ReviewKind(%d).String() = %q, want %q", int(tt.k), got, tt.want)
+		}
+	}
+}
+
+func TestReviewKindJSONRoundTrip(t *testing.T) {
+	for _, k := range []ReviewKind{KindRated, KindLearn, KindReview, KindRelearn, KindFiltered, KindManual, KindReset} {
+		data, err := json.Marshal(k)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", k, err)
+		}
+		if string(data) != `"`+k.String()+`"` {
+			t.Errorf("Marshal(%v) = %s", k, data)
+		}
+		var got ReviewKind
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != k {
+			t.Errorf("round-trip: got %v, want %v", got, k)
+		}
+	}
+}
+
+func TestReviewKindMarshalJSONInvalid(t *testing.T) {
+	if _, err := json.Marshal(ReviewKind(99)); err == nil {
+		t.Error("json.Marshal(ReviewKind(99)) should return error")
+	}
+}
+
+func TestReviewKindUnmarshalJSONInvalid(t *testing.T) {
+	for _, input := range []string{`"Unknown"`, `""`, `1`} {
+		var k ReviewKind
+		if err := json.Unmarshal([]byte(input), &k); err == nil {
+			t.Errorf("json.Unmarshal(%s) should return error", input)
+		}
+	}
+}
+
+// --- ResetCard ---
+
+func TestResetCard(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	orig := reviewCard(t)
+	orig.Reps, orig.Lapses, orig.FirstReview, orig.Leech = 9, 3, ptrT(t0), true
+	orig = s.SuspendCard(orig)
+	now := t0.Add(30 * 24 * time.Hour)
+
+	c, log := s.ResetCard(orig, now, ResetOptions{})
+	if c.CardID != orig.CardID || c.State != Learning || c.Step == nil || *c.Step != 0 {
+		t.Errorf("reset card = %+v, want new Learning card with the same ID", c)
+	}
+	if c.Stability != nil || c.Difficulty != nil || c.LastReview != nil {
+		t.Error("reset card should have no memory state")
+	}
+	if !c.Due.Equal(now) || c.Queue != QueueActive {
+		t.Errorf("Due, Queue = %v, %v, want %v, Active", c.Due, c.Queue, now)
+	}
+	if c.Reps != 9 || c.Lapses != 3 || !c.Leech || c.FirstReview == nil {
+		t.Error("counts should be kept by default")
+	}
+	if orig.State != Review || orig.Stability == nil {
+		t.Error("ResetCard mutated its input")
+	}
+
+	want := ReviewLog{CardID: orig.CardID, ReviewDatetime: now, Kind: KindReset}
+	if log != want {
+		t.Errorf("log = %+v, want %+v", log, want)
+	}
+}
+
+func TestResetCardResetCounts(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	orig := reviewCard(t)
+	orig.Reps, orig.Lapses, orig.FirstReview, orig.Leech = 9, 3, ptrT(t0), true
+
+	c, log := s.ResetCard(orig, t0, ResetOptions{ResetCounts: true})
+	if c.Reps != 0 || c.Lapses != 0 || c.FirstReview != nil || c.Leech {
+		t.Errorf("Reps, Lapses, FirstReview, Leech = %d, %d, %v, %v, want zero", c.Reps, c.Lapses, c.FirstReview, c.Leech)
+	}
+	if !log.ResetCounts {
+		t.Error("log should record ResetCounts")
+	}
+}
+
+func TestResetLogJSON(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	_, log := s.ResetCard(reviewCard(t), t0, ResetOptions{ResetCounts: true})
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), `"rating"`) || !strings.Contains(string(data), `"kind":"Reset"`) {
+		t.Errorf("reset log JSON = %s, want kind Reset and no rating", data)
+	}
+	var got ReviewLog
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got != log {
+		t.Errorf("round-trip = %+v, want %+v", got, log)
+	}
+}
+
+func TestReviewLogJSONWithoutKind(t *testing.T) {
+	var got ReviewLog
+	data := `{"card_id":1,"rating":"Good","review_datetime":"2025-06-15T10:00:00Z"}`
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Kind != KindRated {
+		t.Errorf("Kind = %v, want Rated", got.Kind)
+	}
+}
+
+func TestRescheduleCardReplaysReset(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := NewCard(1)
+	var logs []ReviewLog
+	review := func(r Rating, at time.Time) {
+		var log ReviewLog
+		c, log = s.ReviewCard(c, r, at)
+		logs = append(logs, log)
+	}
+	review(Good, t0)
+	review(Good, t0.Add(10*time.Minute))
+	var log ReviewLog
+	c, log = s.ResetCard(c, t0.Add(3*24*time.Hour), ResetOptions{ResetCounts: true})
+	logs = append(logs, log)
+	review(Easy, t0.Add(4*24*time.Hour))
+
+	got, err := s.RescheduleCard(NewCard(1), logs)
+	if err != nil {
+		t.Fatalf("RescheduleCard: %v", err)
+	}
+	if got.State != c.State || *got.Stability != *c.Stability || !got.Due.Equal(c.Due) {
+		t.Errorf("replayed card = %+v, want %+v", got, c)
+	}
+	if got.Reps != 1 || !got.FirstReview.Equal(t0.Add(4*24*time.Hour)) {
+		t.Errorf("Reps, FirstReview = %d, %v, want 1, first review after reset", got.Reps, got.FirstReview)
+	}
+}
@@ -0,0 +1,131 @@
+package flux
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+)
+
+// ReviewKind says what kind of event a ReviewLog records.
+type ReviewKind int
+
+const (
+	// KindRated is a rated review of unrecorded phase. It is the zero
+	// value, so logs written before kinds existed read as rated reviews.
+	KindRated ReviewKind = iota
+	// KindLearn is a rated review of a card in Learning state.
+	KindLearn
+	// KindReview is a rated review of a card in Review state.
+	KindReview
+	// KindRelearn is a rated review of a card in Relearning state.
+	KindRelearn
+	// KindFiltered is a rated review in a filtered (cram) session that does
+	// not reschedule the card. ReviewCard never records it; callers that
+	// run such sessions log it themselves.
+	KindFiltered
+	// KindManual moves the card's due date, as recorded by SetDueDate.
+	// The log carries no rating; its Due holds the new due date.
+	KindManual
+	// KindReset returns the card to new, as recorded by ResetCard.
+	// The log carries no rating.
+	KindReset
+)
+
+var (
+	reviewKindNames = [...]string{
+		KindRated:    "Rated",
+		KindLearn:    "Learn",
+		KindReview:   "Review",
+		KindRelearn:  "Relearn",
+		KindFiltered: "Filtered",
+		KindManual:   "Manual",
+		KindReset:    "Reset",
+	}
+	reviewKindByName = map[string]ReviewKind{
+		"Rated":    KindRated,
+		"Learn":    KindLearn,
+		"Review":   KindReview,
+		"Relearn":  KindRelearn,
+		"Filtered": KindFiltered,
+		"Manual":   KindManual,
+		"Reset":    KindReset,
+	}
+)
+
+// kindForState returns the review kind of a rated review of a card in state.
+func kindForState(state State) ReviewKind {
+	switch state {
+	case Learning:
+		return KindLearn
+	case Review:
+		return KindReview
+	case Relearning:
+		return KindRelearn
+	default:
+		return KindRated
+	}
+}
+
+// rated reports whether a log of kind k carries a rating.
+func (k ReviewKind) rated() bool {
+	return k >= KindRated && k <= KindFiltered
+}
+
+// Compile-time interface checks.
+var (
+	_ fmt.Stringer             = ReviewKind(0)
+	_ json.Marshaler           = ReviewKind(0)
+	_ json.Unmarshaler         = (*ReviewKind)(nil)
+	_ encoding.TextMarshaler   = ReviewKind(0)
+	_ encoding.TextUnmarshaler = (*ReviewKind)(nil)
+)
+
+func (k ReviewKind) isValid() bool {
+	return k >= KindRated && k <= KindReset
+}
+
+// String returns the name of the review kind ("Rated", "Learn", "Review",
+// "Relearn", "Filtered", "Manual", "Reset").
+// For invalid values it returns "ReviewKind(n)".
+func (k ReviewKind) String() string {
+	if k.isValid() {
+		return reviewKindNames[k]
+	}
+	return fmt.Sprintf("ReviewKind(%d)", int(k))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k ReviewKind) MarshalText() ([]byte, error) {
+	if !k.isValid() {
+		return nil, fmt.Errorf("flux: invalid review kind: %d", int(k))
+	}
+	return []byte(reviewKindNames[k]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *ReviewKind) UnmarshalText(text []byte) error {
+	v, ok := reviewKindByName[string(text)]
+	if !ok {
+		return fmt.Errorf("flux: invalid review kind: %q", text)
+	}
+	*k = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. ReviewKind serializes as a JSON string.
+func (k ReviewKind) MarshalJSON() ([]byte, error) {
+	text, err := k.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Expects a JSON string.
+func (k *ReviewKind) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("flux: invalid review kind: %s", data)
+	}
+	return k.UnmarshalText([]byte(str))
+}
@@ -4,8 +4,30 @@ import "time"
 
 // ReviewLog records a single review event for a card.
 type ReviewLog struct {
-	CardID         int64     `json:"card_id"`
-	Rating         Rating    `json:"rating"`
-	ReviewDatetime time.Time `json:"review_datetime"`
-	ReviewDuration *int      `json:"review_duration,omitempty"` // milliseconds, optional.
+	CardID         int64           `json:"card_id"`
+	CardKey        string          `json:"card_key,omitempty"` // The card's Key, if it has one.
+	Rating         Rating          `json:"rating,omitempty"`   // zero for unrated kinds such as KindReset.
+	ReviewDatetime time.Time       `json:"review_datetime"`
+	ReviewDuration *int            `json:"review_duration,omitempty"` // milliseconds, optional.
+	Leech          bool            `json:"leech,omitempty"`           // The lapse hit a leech threshold or warning.
+	Kind           ReviewKind      `json:"kind,omitempty"`            // zero → KindRated.
+	ResetCounts    bool            `json:"reset_counts,omitempty"`    // KindReset only: counts were cleared.
+	Due            *time.Time      `json:"due,omitempty"`             // KindManual only: the new due date.
+	Snapshot       *ReviewSnapshot `json:"snapshot,omitempty"`        // Filled in by ReviewCard with SchedulerConfig.RecordSnapshots.
+}
+
+// ReviewSnapshot records the card's memory state around a rated review.
+type ReviewSnapshot struct {
+	ElapsedDays   float64 `json:"elapsed_days"`   // Days since the previous review, as the model saw them.
+	ScheduledDays float64 `json:"scheduled_days"` // Days from the review to the new due date, as the model sees them.
+
+	StateBefore          State    `json:"state_before"`
+	StabilityBefore      *float64 `json:"stability_before"`      // nil for a new card.
+	DifficultyBefore     *float64 `json:"difficulty_before"`     // nil for a new card.
+	RetrievabilityBefore float64  `json:"retrievability_before"` // At review time; 0 for a new card.
+
+	StateAfter          State   `json:"state_after"`
+	StabilityAfter      float64 `json:"stability_after"`
+	DifficultyAfter     float64 `json:"difficulty_after"`
+	RetrievabilityAfter float64 `json:"retrievability_after"` // Predicted at the new due date.
 }
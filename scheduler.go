@@ -3,7 +3,7 @@ package flux
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,17 +16,38 @@ type SchedulerConfig struct {
 	RelearningSteps  []time.Duration `json:"relearning_steps"`  // nil → [10m]; empty → no steps
 	MaximumInterval  int             `json:"maximum_interval"`  // zero → 36500
 	DisableFuzzing   bool            `json:"disable_fuzzing"`   // zero false → fuzz enabled
+	Seed             int64           `json:"seed"`              // zero → seeded from the current time
+	FuzzMode         FuzzMode        `json:"fuzz_mode"`         // zero → FuzzRandom
+	LoadBalancer     LoadBalancer    `json:"-"`                 // nil → uniform fuzz; not serialized
+	WeekdayWeights   [7]float64      `json:"weekday_weights"`   // zero array → 1 for every day; indexed by time.Weekday
+	Location         *time.Location  `json:"-"`                 // nil → no day boundary: exact 24h days; serialized by name or fixed offset
+	DayStartHour     int             `json:"day_start_hour"`    // zero → midnight; hour in Location the learner's day starts; requires Location
+	Leech            LeechPolicy     `json:"leech"`             // zero → no leech detection
+	RecordSnapshots  bool            `json:"record_snapshots"`  // zero false → ReviewLog.Snapshot left nil
+	Clock            Clock           `json:"-"`                 // nil → system clock; not serialized
 }
 
 // Scheduler schedules card reviews using the FSRS v6 algorithm.
+// A Scheduler is safe for concurrent use by multiple goroutines.
 type Scheduler struct {
 	algo             algo
 	desiredRetention float64
+	retentionTerm    float64 // algo.retentionTerm(desiredRetention)
 	learningSteps    []time.Duration
 	relearningSteps  []time.Duration
 	maximumInterval  int
 	disableFuzzing   bool
-	rng              *rand.Rand
+	fuzzMode         FuzzMode
+	loadBalancer     LoadBalancer
+	weekdayWeights   [7]float64
+	easyDays         bool           // weekdayWeights are not all equal
+	location         *time.Location // nil → day-boundary mode off
+	dayStartHour     int
+	leech            LeechPolicy
+	recordSnapshots  bool
+	seed             int64
+	draws            *atomic.Uint64 // fuzz values drawn from the seed's stream
+	clock            Clock
 }
 
 // NewScheduler creates a Scheduler from the given config.
@@ -59,6 +80,35 @@ func NewScheduler(cfg SchedulerConfig) (*Scheduler, error) {
 		return nil, fmt.Errorf("flux: maximum interval %d must be positive", maxIvl)
 	}
 
+	if !cfg.FuzzMode.isValid() {
+		return nil, fmt.Errorf("flux: invalid fuzz mode: %d", int(cfg.FuzzMode))
+	}
+
+	// WeekdayWeights: zero array → every day at full capacity.
+	ww := cfg.WeekdayWeights
+	if ww == [7]float64{} {
+		ww = [7]float64{1, 1, 1, 1, 1, 1, 1}
+	}
+	if err := validateWeekdayWeights(ww); err != nil {
+		return nil, err
+	}
+	easyDays := false
+	for _, v := range ww {
+		easyDays = easyDays || v != ww[0]
+	}
+
+	if cfg.DayStartHour < 0 || cfg.DayStartHour > 23 {
+		return nil, fmt.Errorf("flux: day start hour %d out of range [0, 23]", cfg.DayStartHour)
+	}
+	if cfg.DayStartHour != 0 && cfg.Location == nil {
+		return nil, fmt.Errorf("flux: day start hour %d requires a Location", cfg.DayStartHour)
+	}
+
+	leech, err := cfg.Leech.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
 	// LearningSteps: nil → defaults.
 	ls := cfg.LearningSteps
 	if ls == nil {
@@ -71,30 +121,89 @@ func NewScheduler(cfg SchedulerConfig) (*Scheduler, error) {
 		rs = []time.Duration{10 * time.Minute}
 	}
 
+	// Clock: nil → system clock.
+	clock := cfg.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	// Seed: zero → current time. Schedulers sharing a non-zero seed
+	// produce identical fuzzed intervals for identical review sequences.
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = clock.Now().UnixNano()
+	}
+
+	a := newAlgo(params)
 	return &Scheduler{
-		algo:             newAlgo(params),
+		algo:             a,
 		desiredRetention: dr,
+		retentionTerm:    a.retentionTerm(dr),
 		learningSteps:    ls,
 		relearningSteps:  rs,
 		maximumInterval:  maxIvl,
 		disableFuzzing:   cfg.DisableFuzzing,
-		rng:              rand.New(rand.NewSource(time.Now().UnixNano())),
+		fuzzMode:         cfg.FuzzMode,
+		loadBalancer:     cfg.LoadBalancer,
+		weekdayWeights:   ww,
+		easyDays:         easyDays,
+		location:         cfg.Location,
+		dayStartHour:     cfg.DayStartHour,
+		leech:            leech,
+		recordSnapshots:  cfg.RecordSnapshots,
+		seed:             seed,
+		draws:            new(atomic.Uint64),
+		clock:            clock,
 	}, nil
 }
 
+// NewCard creates a new card with the given ID, due at the Scheduler's
+// clock's current time.
+func (s *Scheduler) NewCard(id int64) Card {
+	return NewCardAt(id, s.clock.Now())
+}
+
 // ReviewCard processes a review of the card at the given time.
 // It returns the updated card and a review log. The input card is not mutated.
+// The rating and card are trusted; use TryReviewCard for untrusted input.
 func (s *Scheduler) ReviewCard(card Card, rating Rating, now time.Time) (Card, ReviewLog) {
+	return s.review(card, rating, now, s.fuzzSource(card))
+}
+
+// TryReviewCard is ReviewCard for untrusted input. Instead of panicking or
+// producing garbage, it returns an error wrapping ErrInvalidRating for a
+// rating outside Again..Easy, ErrInvalidCard if card.Validate fails, or
+// ErrTimeTravel if now is before the card's LastReview.
+func (s *Scheduler) TryReviewCard(card Card, rating Rating, now time.Time) (Card, ReviewLog, error) {
+	if !rating.IsValid() {
+		return Card{}, ReviewLog{}, fmt.Errorf("%w: %d", ErrInvalidRating, int(rating))
+	}
+	if err := card.Validate(); err != nil {
+		return Card{}, ReviewLog{}, err
+	}
+	if card.LastReview != nil && now.Before(*card.LastReview) {
+		return Card{}, ReviewLog{}, fmt.Errorf("%w: card %d reviewed at %v, last reviewed at %v",
+			ErrTimeTravel, card.CardID, now, *card.LastReview)
+	}
+	c, log := s.ReviewCard(card, rating, now)
+	return c, log, nil
+}
+
+// review implements ReviewCard, taking its fuzz value from fuzz.
+func (s *Scheduler) review(card Card, rating Rating, now time.Time, fuzz func() float64) (Card, ReviewLog) {
 	c := card.clone()
 
-	// Compute elapsed days since last review.
-	var elapsedDays float64
+	// Compute elapsed days and retrievability since last review.
+	var elapsedDays, r float64
 	if c.LastReview != nil {
-		elapsedDays = now.Sub(*c.LastReview).Hours() / 24.0
+		elapsedDays = s.elapsedDays(*c.LastReview, now)
+		if c.Stability != nil {
+			r = s.algo.retrievability(elapsedDays, *c.Stability)
+		}
 	}
 
 	// Update stability and difficulty.
-	s.updateMemory(&c, rating, elapsedDays)
+	s.updateMemory(&c, rating, elapsedDays, r)
 
 	// Determine steps for current state.
 	steps := s.stepsForState(c.State)
@@ -102,48 +211,213 @@ func (s *Scheduler) ReviewCard(card Card, rating Rating, now time.Time) (Card, R
 	// State transition and interval.
 	interval := s.transition(&c, rating, steps)
 
-	// Apply fuzz if enabled and final state is Review.
-	if !s.disableFuzzing && c.State == Review {
+	// Fuzz, weekday weighting and day snapping apply only when the final
+	// state is Review.
+	c.Due = now.Add(interval)
+	if c.State == Review {
 		days := int(interval.Hours() / 24.0)
-		if days > 0 {
-			fuzzed := applyFuzz(days, s.maximumInterval, s.rng)
-			interval = time.Duration(fuzzed) * 24 * time.Hour
+		switch {
+		case card.State == Review && rating != Again:
+			c.Due = s.dueAt(now, s.orderedInterval(card, rating, days, elapsedDays, r, now, fuzz))
+		case days > 0:
+			c.Due = s.dueAt(now, s.reviewInterval(days, 1, now, fuzz))
 		}
 	}
-
-	c.Due = now.Add(interval)
 	c.LastReview = &now
 
+	// Review-count bookkeeping and leech detection.
+	c.Reps++
+	leech := false
+	if card.State == Review && rating == Again {
+		c.Lapses++
+		leech = s.leech.isLeech(c.Lapses)
+	}
+	if c.Queue == QueueBuried {
+		// Reviewing a buried card lifts the bury.
+		c.Queue, c.BuriedUntil = QueueActive, nil
+	}
+	if leech {
+		c.Leech = true
+		if s.leech.Action == LeechSuspend {
+			c.Queue = QueueSuspended
+		}
+	}
+	if c.FirstReview == nil {
+		first := now
+		c.FirstReview = &first
+	}
+
 	log := ReviewLog{
 		CardID:         c.CardID,
+		CardKey:        c.Key,
 		Rating:         rating,
 		ReviewDatetime: now,
+		Leech:          leech,
+		Kind:           kindForState(card.State),
+	}
+	if s.recordSnapshots {
+		log.Snapshot = s.snapshot(card, c, elapsedDays, r, now)
 	}
 
 	return c, log
 }
 
+// snapshot records the memory state of before and after around a review at
+// now, with r the retrievability of before at now.
+func (s *Scheduler) snapshot(before, after Card, elapsedDays, r float64, now time.Time) *ReviewSnapshot {
+	scheduled := s.elapsedDays(now, after.Due)
+	snap := &ReviewSnapshot{
+		ElapsedDays:         elapsedDays,
+		ScheduledDays:       scheduled,
+		StateBefore:         before.State,
+		StateAfter:          after.State,
+		StabilityAfter:      *after.Stability,
+		DifficultyAfter:     *after.Difficulty,
+		RetrievabilityAfter: s.algo.retrievability(scheduled, *after.Stability),
+	}
+	// Copy the pointer fields: before is the caller's card.
+	if before.Stability != nil {
+		v := *before.Stability
+		snap.StabilityBefore = &v
+		snap.RetrievabilityBefore = r
+	}
+	if before.Difficulty != nil {
+		v := *before.Difficulty
+		snap.DifficultyBefore = &v
+	}
+	return snap
+}
+
+// orderedInterval returns the interval in days for a passing review of a
+// card already in Review state, keeping Hard < Good < Easy as Anki does.
+// days is the unadjusted interval for rating; the other ratings' intervals
+// are computed from card's memory state as needed, with r its
+// retrievability at the review.
+// Hard is capped at Good's unadjusted interval, and each rating is then
+// fuzzed within its own bounds: Good above the final Hard interval and
+// Easy above the final Good interval. All three share one fuzz value.
+func (s *Scheduler) orderedInterval(card Card, rating Rating, days int, elapsedDays, r float64, now time.Time, fuzz func() float64) int {
+	// Easy's interval matters only when Easy was chosen.
+	var raw [3]int // Hard, Good, Easy
+	for g := Hard; g <= max(rating, Good); g++ {
+		if g == rating {
+			raw[g-Hard] = days
+			continue
+		}
+		raw[g-Hard] = s.nextInterval(s.nextStabilityFor(&card, g, elapsedDays, r))
+	}
+
+	ivl := s.reviewInterval(min(raw[0], raw[1]), 1, now, fuzz)
+	for i := Good; i <= rating; i++ {
+		ivl = s.reviewInterval(raw[i-Hard], ivl+1, now, fuzz)
+	}
+	return ivl
+}
+
+// reviewInterval picks the final interval in days for a card entering
+// Review, no shorter than minDays unless that exceeds the maximum interval.
+// With fuzzing enabled the interval is fuzzed, weighted toward less busy days
+// by a LoadBalancer and toward higher-capacity weekdays by WeekdayWeights.
+// With fuzzing disabled only the weekday weights apply, deterministically.
+func (s *Scheduler) reviewInterval(days, minDays int, now time.Time, fuzz func() float64) int {
+	if s.disableFuzzing {
+		if !s.easyDays {
+			return max(days, min(minDays, s.maximumInterval))
+		}
+		return easiestDay(days, minDays, s.maximumInterval, func(d int) float64 {
+			return s.weekdayWeights[s.dueAt(now, d).Weekday()]
+		})
+	}
+
+	if s.loadBalancer == nil && !s.easyDays {
+		return applyFuzz(days, minDays, s.maximumInterval, fuzz())
+	}
+	return weightedFuzz(days, minDays, s.maximumInterval, fuzz(), func(d int) float64 {
+		return s.dayWeight(d, s.dueAt(now, d))
+	})
+}
+
+// fuzzSource returns a function yielding the card's fuzz value. The value
+// is drawn on first use only, so every interval computed for one review,
+// or for all ratings of one preview, shares it.
+func (s *Scheduler) fuzzSource(card Card) func() float64 {
+	var u float64
+	drawn := false
+	return func() float64 {
+		if !drawn {
+			u, drawn = s.fuzzFactor(card), true
+		}
+		return u
+	}
+}
+
+// dayWeight returns the relative preference for scheduling a card d days
+// out, on due: the load-balancing weight times the weekday's capacity.
+func (s *Scheduler) dayWeight(d int, due time.Time) float64 {
+	w := s.weekdayWeights[due.Weekday()]
+	if s.loadBalancer != nil {
+		w *= loadWeight(s.loadBalancer, d, due)
+	}
+	return w
+}
+
+// fuzzFactor returns the uniform random value used to fuzz the card's next interval.
+func (s *Scheduler) fuzzFactor(card Card) float64 {
+	if s.fuzzMode == FuzzCardSeeded {
+		return cardFuzzFactor(card)
+	}
+	return streamFuzzFactor(s.seed, s.draws.Add(1))
+}
+
 // PreviewCard returns the result of reviewing the card with each possible rating.
+// All ratings share one fuzz value, so Hard < Good < Easy holds for a card in
+// Review state. With FuzzCardSeeded, each preview matches the card ReviewCard
+// later returns for that rating at the same time.
 func (s *Scheduler) PreviewCard(card Card, now time.Time) map[Rating]Card {
 	result := make(map[Rating]Card, 4)
+	fuzz := s.fuzzSource(card)
 	for _, r := range []Rating{Again, Hard, Good, Easy} {
-		c, _ := s.ReviewCard(card, r, now)
+		c, _ := s.review(card, r, now, fuzz)
 		result[r] = c
 	}
 	return result
 }
 
 // RescheduleCard replays the given review logs to rebuild the card's scheduling state.
-// Returns ErrCardIDMismatch if any log's CardID does not match the card's CardID.
+// Reps, Lapses and FirstReview are rebuilt along the way, counting on from
+// the card's own values. KindReset logs are replayed with ResetCard and
+// KindManual logs with SetDueDate; neither counts as a rated review.
+// KindFiltered logs are skipped. Logs are replayed in the order given; use
+// RescheduleCardWith to check or repair them first.
+// Returns ErrCardIDMismatch if any log's CardID or CardKey does not match the
+// card's CardID or Key.
 func (s *Scheduler) RescheduleCard(card Card, logs []ReviewLog) (Card, error) {
+	for _, log := range logs {
+		if err := card.matches(log); err != nil {
+			return Card{}, err
+		}
+	}
+	return s.replay(card, logs), nil
+}
+
+// replay applies logs to card in order. CardIDs must already match.
+func (s *Scheduler) replay(card Card, logs []ReviewLog) Card {
 	c := card.clone()
 	for _, log := range logs {
-		if log.CardID != c.CardID {
-			return Card{}, fmt.Errorf("%w: card %d, log %d", ErrCardIDMismatch, c.CardID, log.CardID)
+		switch log.Kind {
+		case KindReset:
+			c, _ = s.ResetCard(c, log.ReviewDatetime, ResetOptions{ResetCounts: log.ResetCounts})
+		case KindManual:
+			if log.Due != nil {
+				c, _ = s.SetDueDate(c, *log.Due, log.ReviewDatetime)
+			}
+		case KindFiltered:
+			// Cram reviews leave the schedule untouched.
+		default:
+			c, _ = s.ReviewCard(c, log.Rating, log.ReviewDatetime)
 		}
-		c, _ = s.ReviewCard(c, log.Rating, log.ReviewDatetime)
 	}
-	return c, nil
+	return c
 }
 
 // schedulerJSON is the serialized form of a Scheduler.
@@ -154,15 +428,41 @@ type schedulerJSON struct {
 	RelearningSteps  []int64     `json:"relearning_steps"` // nanoseconds
 	MaximumInterval  int         `json:"maximum_interval"`
 	DisableFuzzing   bool        `json:"disable_fuzzing"`
+	Seed             int64       `json:"seed"`
+	FuzzMode         FuzzMode    `json:"fuzz_mode"`
+	WeekdayWeights   [7]float64  `json:"weekday_weights"`
+	Location         string      `json:"location,omitempty"`        // time.Location name
+	LocationOffset   *int        `json:"location_offset,omitempty"` // seconds east of UTC, for a fixed zone not loadable by name
+	DayStartHour     int         `json:"day_start_hour"`
+	Leech            LeechPolicy `json:"leech"`
+	RecordSnapshots  bool        `json:"record_snapshots,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler.
+// The effective fuzz seed is serialized, so an unmarshaled Scheduler replays
+// the same fuzz sequence from its start, even if the seed was time-derived.
 func (s *Scheduler) MarshalJSON() ([]byte, error) {
 	j := schedulerJSON{
 		Parameters:       s.algo.w,
 		DesiredRetention: s.desiredRetention,
 		MaximumInterval:  s.maximumInterval,
 		DisableFuzzing:   s.disableFuzzing,
+		Seed:             s.seed,
+		FuzzMode:         s.fuzzMode,
+		WeekdayWeights:   s.weekdayWeights,
+		DayStartHour:     s.dayStartHour,
+		Leech:            s.leech,
+		RecordSnapshots:  s.recordSnapshots,
+	}
+	if s.location != nil {
+		j.Location = s.location.String()
+		if loc, err := time.LoadLocation(j.Location); err != nil || !sameOffsets(loc, s.location) {
+			offset, ok := fixedOffset(s.location)
+			if !ok {
+				return nil, fmt.Errorf("flux: scheduler location %q is neither loadable by name nor a fixed offset", j.Location)
+			}
+			j.LocationOffset = &offset
+		}
 	}
 	j.LearningSteps = durationsToNanos(s.learningSteps)
 	j.RelearningSteps = durationsToNanos(s.relearningSteps)
@@ -171,6 +471,10 @@ func (s *Scheduler) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON implements json.Unmarshaler.
 // It rebuilds the internal precomputed state from the serialized config.
+// A LoadBalancer or Clock is not serialized; one already set on the receiver
+// is kept.
+// The Location is restored by name with time.LoadLocation, or as a
+// time.FixedZone if it was serialized with its offset.
 func (s *Scheduler) UnmarshalJSON(data []byte) error {
 	var j schedulerJSON
 	if err := json.Unmarshal(data, &j); err != nil {
@@ -181,13 +485,33 @@ func (s *Scheduler) UnmarshalJSON(data []byte) error {
 		DesiredRetention: j.DesiredRetention,
 		MaximumInterval:  j.MaximumInterval,
 		DisableFuzzing:   j.DisableFuzzing,
+		Seed:             j.Seed,
+		FuzzMode:         j.FuzzMode,
+		WeekdayWeights:   j.WeekdayWeights,
+		DayStartHour:     j.DayStartHour,
+		Leech:            j.Leech,
+		RecordSnapshots:  j.RecordSnapshots,
 		LearningSteps:    nanosToDurations(j.LearningSteps),
 		RelearningSteps:  nanosToDurations(j.RelearningSteps),
 	}
+	switch {
+	case j.LocationOffset != nil:
+		cfg.Location = time.FixedZone(j.Location, *j.LocationOffset)
+	case j.Location != "":
+		loc, err := time.LoadLocation(j.Location)
+		if err != nil {
+			return fmt.Errorf("flux: scheduler location: %w", err)
+		}
+		cfg.Location = loc
+	}
 	rebuilt, err := NewScheduler(cfg)
 	if err != nil {
 		return err
 	}
+	rebuilt.loadBalancer = s.loadBalancer
+	if s.clock != nil {
+		rebuilt.clock = s.clock
+	}
 	*s = *rebuilt
 	return nil
 }
@@ -212,36 +536,45 @@ func nanosToDurations(ns []int64) []time.Duration {
 }
 
 // Retrievability returns the probability of recall for the card at the given time.
+// In day-boundary mode elapsed time counts whole learner days, as in ReviewCard.
 // Returns 0 if the card has never been reviewed or has no stability.
 func (s *Scheduler) Retrievability(card Card, now time.Time) float64 {
 	if card.LastReview == nil || card.Stability == nil {
 		return 0
 	}
-	elapsed := now.Sub(*card.LastReview).Hours() / 24.0
-	return s.algo.retrievability(elapsed, *card.Stability)
+	return s.algo.retrievability(s.elapsedDays(*card.LastReview, now), *card.Stability)
 }
 
-// updateMemory updates the card's stability and difficulty based on the review.
-func (s *Scheduler) updateMemory(c *Card, rating Rating, elapsedDays float64) {
+// updateMemory updates the card's stability and difficulty based on the
+// review, with r the card's retrievability at the review.
+func (s *Scheduler) updateMemory(c *Card, rating Rating, elapsedDays, r float64) {
+	stability := s.nextStabilityFor(c, rating, elapsedDays, r)
 	if c.Stability == nil {
-		// First review: initialize S and D.
-		c.setStability(s.algo.initStability(rating))
+		// First review: initialize D.
 		c.setDifficulty(s.algo.initDifficulty(rating, true))
+		c.setStability(stability)
 		return
 	}
+	// c is a clone, so its pointers are its own to update in place.
+	*c.Difficulty = s.algo.nextDifficulty(*c.Difficulty, rating)
+	*c.Stability = stability
+}
 
-	stability := *c.Stability
-	difficulty := *c.Difficulty
-
-	if elapsedDays < 1 {
+// nextStabilityFor returns the card's stability after a review with the
+// given rating, elapsedDays after its last review at retrievability r.
+// The card is not modified.
+func (s *Scheduler) nextStabilityFor(c *Card, rating Rating, elapsedDays, r float64) float64 {
+	switch {
+	case c.Stability == nil:
+		// First review: initialize S.
+		return s.algo.initStability(rating)
+	case elapsedDays < 1:
 		// Same-day review.
-		c.setStability(s.algo.shortTermStability(stability, rating))
-	} else {
+		return s.algo.shortTermStability(*c.Stability, rating)
+	default:
 		// Cross-day review.
-		r := s.algo.retrievability(elapsedDays, stability)
-		c.setStability(s.algo.nextStability(difficulty, stability, r, rating))
+		return s.algo.nextStability(*c.Difficulty, *c.Stability, r, rating)
 	}
-	c.setDifficulty(s.algo.nextDifficulty(difficulty, rating))
 }
 
 // stepsForState returns the step durations for the given state.
@@ -319,14 +652,20 @@ func (s *Scheduler) transitionReview(c *Card, rating Rating) time.Duration {
 
 	// Hard, Good, Easy, or Again with empty relearning steps.
 	c.clearStep()
-	days := s.algo.nextInterval(*c.Stability, s.desiredRetention, s.maximumInterval)
+	days := s.nextInterval(*c.Stability)
 	return time.Duration(days) * 24 * time.Hour
 }
 
+// nextInterval returns the unfuzzed interval in days for stability at the
+// desired retention.
+func (s *Scheduler) nextInterval(stability float64) int {
+	return s.algo.intervalWithTerm(stability, s.retentionTerm, s.maximumInterval)
+}
+
 // graduateToReview transitions a card from Learning/Relearning to Review.
 func (s *Scheduler) graduateToReview(c *Card) time.Duration {
 	c.State = Review
 	c.clearStep()
-	days := s.algo.nextInterval(*c.Stability, s.desiredRetention, s.maximumInterval)
+	days := s.nextInterval(*c.Stability)
 	return time.Duration(days) * 24 * time.Hour
 }
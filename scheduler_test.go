@@ -55,6 +55,12 @@ func TestNewSchedulerInvalidRetention(t *testing.T) {
 	}
 }
 
+func TestNewSchedulerInvalidFuzzMode(t *testing.T) {
+	if _, err := NewScheduler(SchedulerConfig{FuzzMode: FuzzMode(9)}); err == nil {
+		t.Error("NewScheduler should reject an invalid fuzz mode")
+	}
+}
+
 func TestNewSchedulerInvalidMaxInterval(t *testing.T) {
 	cfg := SchedulerConfig{MaximumInterval: -1}
 	_, err := NewScheduler(cfg)
@@ -474,6 +480,82 @@ func TestFuzzDisabledStableInterval(t *testing.T) {
 	}
 }
 
+func TestFuzzSeedReproducible(t *testing.T) {
+	s1 := mustScheduler(t, SchedulerConfig{Seed: 7})
+	s2 := mustScheduler(t, SchedulerConfig{Seed: 7})
+	card := reviewCard(t)
+	t1 := t0.Add(10 * 24 * time.Hour)
+
+	for i := 0; i < 20; i++ {
+		c1, _ := s1.ReviewCard(card, Good, t1)
+		c2, _ := s2.ReviewCard(card, Good, t1)
+		if !c1.Due.Equal(c2.Due) {
+			t.Fatalf("review %d: Due %v != %v with same seed", i, c1.Due, c2.Due)
+		}
+	}
+}
+
+func TestFuzzZeroSeedTimeDerived(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{})
+	if s.seed == 0 {
+		t.Error("zero Seed should be replaced by a time-derived seed")
+	}
+}
+
+func TestFuzzCardSeededPreviewMatchesReview(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{FuzzMode: FuzzCardSeeded})
+	t1 := t0.Add(10 * 24 * time.Hour)
+	for id := int64(1); id <= 20; id++ {
+		card := reviewCard(t)
+		card.CardID = id
+		previews := s.PreviewCard(card, t1)
+		for _, r := range []Rating{Hard, Good, Easy} {
+			reviewed, _ := s.ReviewCard(card, r, t1)
+			if !previews[r].Due.Equal(reviewed.Due) {
+				t.Errorf("card %d %v: preview Due %v != review Due %v", id, r, previews[r].Due, reviewed.Due)
+			}
+		}
+	}
+}
+
+func TestFuzzCardSeededVariesAcrossCards(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{FuzzMode: FuzzCardSeeded})
+	t1 := t0.Add(10 * 24 * time.Hour)
+	intervals := make(map[int]bool)
+	for id := int64(1); id <= 50; id++ {
+		card := reviewCard(t)
+		card.CardID = id
+		c, _ := s.ReviewCard(card, Good, t1)
+		intervals[int(math.Round(c.Due.Sub(t1).Hours()/24.0))] = true
+	}
+	if len(intervals) < 2 {
+		t.Errorf("card-seeded fuzz should vary across cards, got %d unique intervals", len(intervals))
+	}
+}
+
+func TestFuzzCardSeededRescheduleStable(t *testing.T) {
+	logs := []ReviewLog{
+		{CardID: 9, Rating: Good, ReviewDatetime: t0},
+		{CardID: 9, Rating: Good, ReviewDatetime: t0.Add(10 * time.Minute)},
+		{CardID: 9, Rating: Good, ReviewDatetime: t0.Add(4 * 24 * time.Hour)},
+		{CardID: 9, Rating: Good, ReviewDatetime: t0.Add(20 * 24 * time.Hour)},
+	}
+	// Different seeds: card-seeded fuzz ignores the scheduler's stream.
+	s1 := mustScheduler(t, SchedulerConfig{FuzzMode: FuzzCardSeeded, Seed: 1})
+	s2 := mustScheduler(t, SchedulerConfig{FuzzMode: FuzzCardSeeded, Seed: 2})
+	c1, err := s1.RescheduleCard(NewCard(9), logs)
+	if err != nil {
+		t.Fatalf("RescheduleCard: %v", err)
+	}
+	c2, err := s2.RescheduleCard(NewCard(9), logs)
+	if err != nil {
+		t.Fatalf("RescheduleCard: %v", err)
+	}
+	if !c1.Due.Equal(c2.Due) {
+		t.Errorf("replay Due %v != %v", c1.Due, c2.Due)
+	}
+}
+
 // --- Retrievability ---
 
 func TestRetrievabilityNilLastReview(t *testing.T) {
@@ -525,6 +607,44 @@ func TestReviewCardSetsLastReview(t *testing.T) {
 
 // --- Input card not mutated ---
 
+func TestReviewCardCounts(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := NewCard(1)
+	steps := []struct {
+		rating     Rating
+		at         time.Time
+		reps, laps int
+	}{
+		{Good, t0, 1, 0},
+		{Again, t0.Add(time.Minute), 2, 0}, // Learning: not a lapse
+		{Good, t0.Add(20 * time.Minute), 3, 0},
+		{Good, t0.Add(time.Hour), 4, 0},
+		{Again, t0.Add(10 * 24 * time.Hour), 5, 1}, // Review → Relearning
+		{Again, t0.Add(10*24*time.Hour + time.Minute), 6, 1},
+		{Good, t0.Add(10*24*time.Hour + time.Hour), 7, 1},
+	}
+	for i, st := range steps {
+		c, _ = s.ReviewCard(c, st.rating, st.at)
+		if c.Reps != st.reps || c.Lapses != st.laps {
+			t.Errorf("review %d: Reps, Lapses = %d, %d, want %d, %d", i, c.Reps, c.Lapses, st.reps, st.laps)
+		}
+		if c.FirstReview == nil || !c.FirstReview.Equal(t0) {
+			t.Errorf("review %d: FirstReview = %v, want %v", i, c.FirstReview, t0)
+		}
+	}
+	if c.FirstReview == c.LastReview {
+		t.Error("FirstReview aliases LastReview")
+	}
+}
+
+func TestReviewCardLapseEmptyRelearningSteps(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{DisableFuzzing: true, RelearningSteps: []time.Duration{}})
+	c, _ := s.ReviewCard(reviewCard(t), Again, t0.Add(5*24*time.Hour))
+	if c.State != Review || c.Lapses != 1 {
+		t.Errorf("State, Lapses = %v, %d, want Review, 1", c.State, c.Lapses)
+	}
+}
+
 func TestReviewCardDoesNotMutateInput(t *testing.T) {
 	s := mustScheduler(t, noFuzzCfg())
 	card := NewCard(1)
@@ -587,6 +707,51 @@ func TestPreviewCardDoesNotMutateInput(t *testing.T) {
 	}
 }
 
+// --- TryReviewCard ---
+
+func TestTryReviewCard(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	card := reviewCard(t)
+	now := t0.Add(3 * 24 * time.Hour)
+	want, wantLog := s.ReviewCard(card, Good, now)
+	got, log, err := s.TryReviewCard(card, Good, now)
+	if err != nil {
+		t.Fatalf("TryReviewCard: %v", err)
+	}
+	if !got.Due.Equal(want.Due) || *got.Stability != *want.Stability || log.Rating != wantLog.Rating {
+		t.Errorf("TryReviewCard = %+v, want %+v", got, want)
+	}
+}
+
+func TestTryReviewCardErrors(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	card := reviewCard(t)
+	later := t0.Add(3 * 24 * time.Hour)
+	broken := card.clone()
+	broken.Difficulty = nil
+	tests := []struct {
+		name   string
+		card   Card
+		rating Rating
+		now    time.Time
+		want   error
+	}{
+		{"rating 0", card, Rating(0), later, ErrInvalidRating},
+		{"rating 5", card, Rating(5), later, ErrInvalidRating},
+		{"invalid card", broken, Good, later, ErrInvalidCard},
+		{"time travel", card, Good, card.LastReview.Add(-time.Second), ErrTimeTravel},
+	}
+	for _, tt := range tests {
+		got, _, err := s.TryReviewCard(tt.card, tt.rating, tt.now)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+		if got.Stability != nil {
+			t.Errorf("%s: returned card %+v, want zero", tt.name, got)
+		}
+	}
+}
+
 // --- RescheduleCard ---
 
 func TestRescheduleCardReplay(t *testing.T) {
@@ -612,6 +777,22 @@ func TestRescheduleCardReplay(t *testing.T) {
 	assertFloat(t, "Difficulty", *got.Difficulty, *c3.Difficulty)
 }
 
+func TestRescheduleCardCounts(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	logs := []ReviewLog{
+		{CardID: 1, Rating: Easy, ReviewDatetime: t0},
+		{CardID: 1, Rating: Again, ReviewDatetime: t0.Add(20 * 24 * time.Hour)},
+		{CardID: 1, Rating: Good, ReviewDatetime: t0.Add(20*24*time.Hour + time.Hour)},
+	}
+	got, err := s.RescheduleCard(NewCard(1), logs)
+	if err != nil {
+		t.Fatalf("RescheduleCard: %v", err)
+	}
+	if got.Reps != 3 || got.Lapses != 1 || !got.FirstReview.Equal(t0) {
+		t.Errorf("Reps, Lapses, FirstReview = %d, %d, %v, want 3, 1, %v", got.Reps, got.Lapses, got.FirstReview, t0)
+	}
+}
+
 func TestRescheduleCardIDMismatch(t *testing.T) {
 	s := mustScheduler(t, noFuzzCfg())
 	card := NewCard(1)
@@ -627,6 +808,30 @@ func TestRescheduleCardIDMismatch(t *testing.T) {
 	}
 }
 
+func TestRescheduleCardKey(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	card := NewCardAt(0, t0)
+	card.Key = "3f0c9a1e-uuid"
+	reviewed, log := s.ReviewCard(card, Good, t0)
+	if log.CardKey != card.Key {
+		t.Errorf("log.CardKey = %q, want %q", log.CardKey, card.Key)
+	}
+	if _, rlog := s.ResetCard(reviewed, t0, ResetOptions{}); rlog.CardKey != card.Key {
+		t.Errorf("reset log CardKey = %q, want %q", rlog.CardKey, card.Key)
+	}
+	if _, dlog := s.SetDueDate(reviewed, t0, t0); dlog.CardKey != card.Key {
+		t.Errorf("manual log CardKey = %q, want %q", dlog.CardKey, card.Key)
+	}
+
+	if _, err := s.RescheduleCard(card, []ReviewLog{log}); err != nil {
+		t.Errorf("RescheduleCard with matching key: %v", err)
+	}
+	log.CardKey = "other"
+	if _, err := s.RescheduleCard(card, []ReviewLog{log}); !errors.Is(err, ErrCardIDMismatch) {
+		t.Errorf("RescheduleCard with another key: error = %v, want ErrCardIDMismatch", err)
+	}
+}
+
 func TestRescheduleCardEmptyLogs(t *testing.T) {
 	s := mustScheduler(t, noFuzzCfg())
 	card := NewCard(1)
@@ -677,6 +882,63 @@ func TestSchedulerJSONRoundTrip(t *testing.T) {
 	assertFloat(t, "Difficulty", *c1.Difficulty, *c2.Difficulty)
 }
 
+func TestSchedulerJSONSeedRoundTrip(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{}) // time-derived seed
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var s2 Scheduler
+	if err := json.Unmarshal(data, &s2); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s2.seed != s.seed {
+		t.Fatalf("seed = %d, want %d", s2.seed, s.seed)
+	}
+
+	// A fresh Scheduler from the same seed replays the same fuzz sequence.
+	s3 := mustScheduler(t, SchedulerConfig{Seed: s.seed})
+	card := reviewCard(t)
+	t1 := t0.Add(30 * 24 * time.Hour)
+	for i := 0; i < 10; i++ {
+		c2, _ := s2.ReviewCard(card, Good, t1)
+		c3, _ := s3.ReviewCard(card, Good, t1)
+		if !c2.Due.Equal(c3.Due) {
+			t.Fatalf("review %d: Due %v != %v after round-trip", i, c2.Due, c3.Due)
+		}
+	}
+}
+
+func TestSchedulerJSONFuzzMode(t *testing.T) {
+	s := mustScheduler(t, SchedulerConfig{FuzzMode: FuzzCardSeeded})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var s2 Scheduler
+	if err := json.Unmarshal(data, &s2); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s2.fuzzMode != FuzzCardSeeded {
+		t.Errorf("fuzzMode = %v, want CardSeeded", s2.fuzzMode)
+	}
+}
+
+func TestSchedulerConfigJSONZero(t *testing.T) {
+	data, err := json.Marshal(SchedulerConfig{})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var cfg SchedulerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if cfg.Leech.Action != LeechTag {
+		t.Errorf("Leech.Action = %v, want Tag", cfg.Leech.Action)
+	}
+}
+
 func TestSchedulerJSONDefaultConfig(t *testing.T) {
 	s := mustScheduler(t, SchedulerConfig{})
 	data, err := json.Marshal(s)
@@ -756,3 +1018,50 @@ func TestSchedulerJSONNullSteps(t *testing.T) {
 		t.Errorf("State = %v, want Learning (default steps from null)", c.State)
 	}
 }
+
+// --- ReviewKind ---
+
+func TestReviewCardKind(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	c := NewCard(1)
+	steps := []struct {
+		rating Rating
+		at     time.Time
+		want   ReviewKind
+	}{
+		{Good, t0, KindLearn},
+		{Good, t0.Add(10 * time.Minute), KindLearn},
+		{Again, t0.Add(5 * 24 * time.Hour), KindReview},
+		{Good, t0.Add(5*24*time.Hour + 10*time.Minute), KindRelearn},
+		{Good, t0.Add(10 * 24 * time.Hour), KindReview},
+	}
+	for i, st := range steps {
+		var log ReviewLog
+		c, log = s.ReviewCard(c, st.rating, st.at)
+		if log.Kind != st.want {
+			t.Errorf("review %d: Kind = %v, want %v", i, log.Kind, st.want)
+		}
+	}
+}
+
+func TestKindForInvalidState(t *testing.T) {
+	if got := kindForState(State(0)); got != KindRated {
+		t.Errorf("kindForState(0) = %v, want Rated", got)
+	}
+}
+
+func TestRescheduleCardSkipsFiltered(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	logs := []ReviewLog{
+		{CardID: 1, Rating: Easy, ReviewDatetime: t0},
+		{CardID: 1, Kind: KindFiltered, Rating: Again, ReviewDatetime: t0.Add(24 * time.Hour)},
+	}
+	got, err := s.RescheduleCard(NewCard(1), logs)
+	if err != nil {
+		t.Fatalf("RescheduleCard: %v", err)
+	}
+	want, _ := s.ReviewCard(NewCard(1), Easy, t0)
+	if got.Reps != 1 || !got.Due.Equal(want.Due) || *got.Stability != *want.Stability {
+		t.Errorf("filtered review changed the schedule: %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+// Package simulator runs collection-level review simulations with FSRS v6.
+//
+// [Simulate] introduces new cards from a deck and reviews due cards one day
+// at a time, driving a [flux.Scheduler] with ratings drawn from the model's
+// own recall probability. Daily limits on new cards, reviews and study time
+// apply as in a real study session. The result is a set of per-day series:
+// reviews done, new cards learned, time spent and cards memorized (the sum
+// of every learned card's retrievability at the end of the day), the same
+// kind of simulation as fsrs-rs simulate.
+//
+// # Usage
+//
+//	res, err := simulator.Simulate(ctx, simulator.Config{
+//	    DeckSize:       5000,
+//	    LearnSpan:      365,
+//	    NewCardsPerDay: 20,
+//	    MaxCostPerDay:  30 * time.Minute,
+//	})
+//	fmt.Println(res.Memorized[len(res.Memorized)-1])
+//
+// # Determinism
+//
+// Ratings come from a random source seeded with Config.Seed, so equal
+// configs give identical results as long as the Scheduler's fuzzing is
+// disabled or uses FuzzCardSeeded. With the default FuzzRandom mode a
+// Scheduler draws from its seeded stream on every review, so only a fresh
+// Scheduler per call reproduces a result; reusing one continues its stream.
+package simulator
@@ -0,0 +1,261 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/sky-flux/flux"
+)
+
+// ErrInvalidConfig is returned when a Config field is out of range.
+var ErrInvalidConfig = errors.New("simulator: invalid config")
+
+// Default rating probabilities and costs, from fsrs-rs.
+var (
+	// DefaultFirstRatingProbs are the probabilities of Again, Hard, Good and
+	// Easy on a card's first review.
+	DefaultFirstRatingProbs = [4]float64{0.24, 0.094, 0.495, 0.171}
+	// DefaultReviewRatingProbs are the probabilities of Hard, Good and Easy
+	// on a later review, given that the card was recalled.
+	DefaultReviewRatingProbs = [3]float64{0.224, 0.632, 0.144}
+	// DefaultLearnCosts are the times spent on a first review, by rating.
+	DefaultLearnCosts = [4]time.Duration{33790 * time.Millisecond, 24300 * time.Millisecond, 13680 * time.Millisecond, 6500 * time.Millisecond}
+	// DefaultReviewCosts are the times spent on a later review, by rating.
+	DefaultReviewCosts = [4]time.Duration{23000 * time.Millisecond, 11680 * time.Millisecond, 7330 * time.Millisecond, 5600 * time.Millisecond}
+)
+
+// Config configures a simulation.
+// Zero values are replaced with sensible defaults; see field comments.
+type Config struct {
+	Scheduler         *flux.Scheduler  `json:"-"`                   // nil → default parameters, fuzzing disabled
+	DeckSize          int              `json:"deck_size"`           // zero → 10000 cards
+	LearnSpan         int              `json:"learn_span"`          // zero → 365 days
+	NewCardsPerDay    int              `json:"new_cards_per_day"`   // zero → 20
+	MaxReviewsPerDay  int              `json:"max_reviews_per_day"` // zero → no limit
+	MaxCostPerDay     time.Duration    `json:"max_cost_per_day"`    // zero → no limit
+	FirstRatingProbs  [4]float64       `json:"first_rating_probs"`  // zero → DefaultFirstRatingProbs; Again..Easy
+	ReviewRatingProbs [3]float64       `json:"review_rating_probs"` // zero → DefaultReviewRatingProbs; Hard..Easy
+	LearnCosts        [4]time.Duration `json:"learn_costs"`         // zero → DefaultLearnCosts; Again..Easy
+	ReviewCosts       [4]time.Duration `json:"review_costs"`        // zero → DefaultReviewCosts; Again..Easy
+	Start             time.Time        `json:"start"`               // zero → 2025-01-01 00:00 UTC
+	Seed              int64            `json:"seed"`                // seeds the rating draws
+}
+
+// Result holds the per-day series of a simulation, indexed by day.
+type Result struct {
+	Reviews   []int           `json:"reviews"`   // Ratings given to cards learned on earlier days.
+	NewCards  []int           `json:"new_cards"` // Cards introduced from the deck.
+	Cost      []time.Duration `json:"cost"`      // Time spent on new cards and reviews.
+	Memorized []float64       `json:"memorized"` // Σ retrievability of learned cards at the end of the day.
+}
+
+// Simulate studies a deck of cfg.DeckSize new cards for cfg.LearnSpan days.
+//
+// Each day starts with the cards already learned that are due before the
+// day ends, most overdue first, skipping cards the Scheduler has suspended
+// as leeches; each is reviewed, through any same-day
+// relearning steps, while the review and time limits allow. New cards are
+// then introduced, and taken through their same-day learning steps, up to
+// NewCardsPerDay and while time remains. Work left over carries to the next
+// day. A card is recalled with the probability the Scheduler predicts; a
+// first review draws from FirstRatingProbs instead.
+//
+// Returns ErrInvalidConfig for a negative limit, a negative cost or
+// probabilities that do not sum to 1. The context is checked once per day.
+func Simulate(ctx context.Context, cfg Config) (Result, error) {
+	sim, err := newSimulation(cfg)
+	if err != nil {
+		return Result{}, err
+	}
+
+	days := sim.cfg.LearnSpan
+	res := Result{
+		Reviews:   make([]int, days),
+		NewCards:  make([]int, days),
+		Cost:      make([]time.Duration, days),
+		Memorized: make([]float64, days),
+	}
+	for day := range days {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		start := sim.cfg.Start.Add(time.Duration(day) * 24 * time.Hour)
+		end := start.Add(24 * time.Hour)
+
+		var d dayLoad
+		for _, i := range sim.due(end) {
+			for dueBefore(sim.cards[i], end) && sim.canReview(d) {
+				d.cost += sim.study(&sim.cards[i], start)
+				d.reviews++
+			}
+		}
+		for d.learned < sim.cfg.NewCardsPerDay && len(sim.cards) < sim.cfg.DeckSize && sim.hasTime(d) {
+			card := flux.NewCardAt(int64(len(sim.cards)+1), start)
+			d.cost += sim.study(&card, start)
+			for card.Due.Before(end) && sim.hasTime(d) {
+				d.cost += sim.study(&card, start)
+			}
+			sim.cards = append(sim.cards, card)
+			d.learned++
+		}
+
+		res.Reviews[day] = d.reviews
+		res.NewCards[day] = d.learned
+		res.Cost[day] = d.cost
+		for _, c := range sim.cards {
+			res.Memorized[day] += sim.s.Retrievability(c, end)
+		}
+	}
+	return res, nil
+}
+
+// dayLoad is the work done so far on the current day.
+type dayLoad struct {
+	reviews, learned int
+	cost             time.Duration
+}
+
+// simulation is the state of a running Simulate call.
+type simulation struct {
+	cfg   Config
+	s     *flux.Scheduler
+	rng   *rand.Rand
+	cards []flux.Card // learned cards, in order of introduction
+}
+
+// newSimulation fills in cfg's defaults and validates it.
+func newSimulation(cfg Config) (*simulation, error) {
+	if cfg.DeckSize == 0 {
+		cfg.DeckSize = 10000
+	}
+	if cfg.LearnSpan == 0 {
+		cfg.LearnSpan = 365
+	}
+	if cfg.NewCardsPerDay == 0 {
+		cfg.NewCardsPerDay = 20
+	}
+	if cfg.FirstRatingProbs == [4]float64{} {
+		cfg.FirstRatingProbs = DefaultFirstRatingProbs
+	}
+	if cfg.ReviewRatingProbs == [3]float64{} {
+		cfg.ReviewRatingProbs = DefaultReviewRatingProbs
+	}
+	if cfg.LearnCosts == [4]time.Duration{} {
+		cfg.LearnCosts = DefaultLearnCosts
+	}
+	if cfg.ReviewCosts == [4]time.Duration{} {
+		cfg.ReviewCosts = DefaultReviewCosts
+	}
+	if cfg.Start.IsZero() {
+		cfg.Start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	switch {
+	case cfg.DeckSize < 0 || cfg.LearnSpan < 0 || cfg.NewCardsPerDay < 0 ||
+		cfg.MaxReviewsPerDay < 0 || cfg.MaxCostPerDay < 0:
+		return nil, fmt.Errorf("%w: negative size, span or limit", ErrInvalidConfig)
+	case !validProbs(cfg.FirstRatingProbs[:]):
+		return nil, fmt.Errorf("%w: first rating probabilities %v", ErrInvalidConfig, cfg.FirstRatingProbs)
+	case !validProbs(cfg.ReviewRatingProbs[:]):
+		return nil, fmt.Errorf("%w: review rating probabilities %v", ErrInvalidConfig, cfg.ReviewRatingProbs)
+	case slices.Min(cfg.LearnCosts[:]) < 0 || slices.Min(cfg.ReviewCosts[:]) < 0:
+		return nil, fmt.Errorf("%w: negative cost", ErrInvalidConfig)
+	}
+
+	s := cfg.Scheduler
+	if s == nil {
+		// The default scheduler config is always valid.
+		s, _ = flux.NewScheduler(flux.SchedulerConfig{DisableFuzzing: true})
+	}
+	return &simulation{
+		cfg: cfg,
+		s:   s,
+		rng: rand.New(rand.NewSource(cfg.Seed)),
+	}, nil
+}
+
+// validProbs reports whether probs are non-negative and sum to 1.
+func validProbs(probs []float64) bool {
+	var sum float64
+	for _, p := range probs {
+		if !(p >= 0) {
+			return false
+		}
+		sum += p
+	}
+	return math.Abs(sum-1) < 1e-6
+}
+
+// due returns the indices of the learned cards due before end, most overdue
+// first.
+func (sim *simulation) due(end time.Time) []int {
+	var idx []int
+	for i, c := range sim.cards {
+		if dueBefore(c, end) {
+			idx = append(idx, i)
+		}
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		return sim.cards[a].Due.Compare(sim.cards[b].Due)
+	})
+	return idx
+}
+
+// dueBefore reports whether card comes up for review before end: it is due
+// by then and not suspended.
+func dueBefore(card flux.Card, end time.Time) bool {
+	return card.Queue != flux.QueueSuspended && card.Due.Before(end)
+}
+
+// canReview reports whether the day's limits allow another review.
+func (sim *simulation) canReview(d dayLoad) bool {
+	if sim.cfg.MaxReviewsPerDay > 0 && d.reviews >= sim.cfg.MaxReviewsPerDay {
+		return false
+	}
+	return sim.hasTime(d)
+}
+
+// hasTime reports whether the day's time budget is not yet spent.
+func (sim *simulation) hasTime(d dayLoad) bool {
+	return sim.cfg.MaxCostPerDay == 0 || d.cost < sim.cfg.MaxCostPerDay
+}
+
+// study reviews card once, no earlier than dayStart, and returns the time
+// the review took.
+func (sim *simulation) study(card *flux.Card, dayStart time.Time) time.Duration {
+	at := card.Due
+	if at.Before(dayStart) {
+		at = dayStart
+	}
+
+	var rating flux.Rating
+	var cost time.Duration
+	if card.Stability == nil {
+		rating = flux.Again + flux.Rating(pick(sim.cfg.FirstRatingProbs[:], sim.rng.Float64()))
+		cost = sim.cfg.LearnCosts[rating-flux.Again]
+	} else {
+		rating = flux.Again
+		if sim.rng.Float64() < sim.s.Retrievability(*card, at) {
+			rating = flux.Hard + flux.Rating(pick(sim.cfg.ReviewRatingProbs[:], sim.rng.Float64()))
+		}
+		cost = sim.cfg.ReviewCosts[rating-flux.Again]
+	}
+	*card, _ = sim.s.ReviewCard(*card, rating, at)
+	return cost
+}
+
+// pick returns the index selected by u in the cumulative distribution probs.
+func pick(probs []float64, u float64) int {
+	for i, p := range probs {
+		if u < p {
+			return i
+		}
+		u -= p
+	}
+	return len(probs) - 1
+}
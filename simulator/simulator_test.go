@@ -0,0 +1,189 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sky-flux/flux"
+)
+
+// small is a quick simulation for tests.
+func small() Config {
+	return Config{DeckSize: 300, LearnSpan: 60, NewCardsPerDay: 10, Seed: 7}
+}
+
+func mustSimulate(t *testing.T, cfg Config) Result {
+	t.Helper()
+	res, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	return res
+}
+
+func TestSimulateDefaults(t *testing.T) {
+	res := mustSimulate(t, Config{LearnSpan: 3})
+	if len(res.Reviews) != 3 || len(res.NewCards) != 3 || len(res.Cost) != 3 || len(res.Memorized) != 3 {
+		t.Fatalf("series lengths = %d, %d, %d, %d, want 3", len(res.Reviews), len(res.NewCards), len(res.Cost), len(res.Memorized))
+	}
+	if res.NewCards[0] != 20 || res.Reviews[0] != 0 {
+		t.Errorf("day 0: new, reviews = %d, %d, want 20, 0", res.NewCards[0], res.Reviews[0])
+	}
+	if res.Cost[0] <= 0 || res.Memorized[0] <= 0 || res.Memorized[0] > 20 {
+		t.Errorf("day 0: cost, memorized = %v, %v", res.Cost[0], res.Memorized[0])
+	}
+	if res := mustSimulate(t, Config{DeckSize: 20}); len(res.Reviews) != 365 {
+		t.Errorf("default learn span = %d days, want 365", len(res.Reviews))
+	}
+}
+
+func TestSimulateDeterministic(t *testing.T) {
+	a, b := mustSimulate(t, small()), mustSimulate(t, small())
+	if !reflect.DeepEqual(a, b) {
+		t.Error("equal configs gave different results")
+	}
+	cfg := small()
+	cfg.Seed = 8
+	if reflect.DeepEqual(a, mustSimulate(t, cfg)) {
+		t.Error("different seeds gave identical results")
+	}
+}
+
+func TestSimulateNewCardLimits(t *testing.T) {
+	cfg := small()
+	cfg.DeckSize = 25
+	res := mustSimulate(t, cfg)
+	if want := []int{10, 10, 5, 0}; !slices.Equal(res.NewCards[:4], want) {
+		t.Errorf("NewCards = %v, want %v", res.NewCards[:4], want)
+	}
+	for day := 1; day < len(res.Memorized); day++ {
+		if res.Memorized[day] > 25 {
+			t.Fatalf("day %d: memorized %v exceeds the deck", day, res.Memorized[day])
+		}
+	}
+}
+
+func TestSimulateMaxReviewsPerDay(t *testing.T) {
+	cfg := small()
+	cfg.MaxReviewsPerDay = 8
+	res := mustSimulate(t, cfg)
+	for day, n := range res.Reviews {
+		if n > 8 {
+			t.Fatalf("day %d: %d reviews, limit 8", day, n)
+		}
+	}
+	if res.Reviews[len(res.Reviews)-1] != 8 {
+		t.Errorf("backlog should keep the last day at the limit, got %d", res.Reviews[len(res.Reviews)-1])
+	}
+}
+
+func TestSimulateMaxCostPerDay(t *testing.T) {
+	cfg := small()
+	cfg.NewCardsPerDay = 50
+	cfg.MaxCostPerDay = 5 * time.Minute
+	res := mustSimulate(t, cfg)
+	slack := slices.Max(DefaultLearnCosts[:]) + slices.Max(DefaultReviewCosts[:])
+	for day, c := range res.Cost {
+		if c >= cfg.MaxCostPerDay+slack {
+			t.Fatalf("day %d: cost %v, budget %v", day, c, cfg.MaxCostPerDay)
+		}
+	}
+	if res.NewCards[0] >= 50 {
+		t.Errorf("time budget should cap new cards, got %d", res.NewCards[0])
+	}
+}
+
+func TestSimulateScheduler(t *testing.T) {
+	reviews := func(retention float64) int {
+		s, err := flux.NewScheduler(flux.SchedulerConfig{DesiredRetention: retention, DisableFuzzing: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg := small()
+		cfg.Scheduler = s
+		var n int
+		for _, r := range mustSimulate(t, cfg).Reviews {
+			n += r
+		}
+		return n
+	}
+	if lo, hi := reviews(0.8), reviews(0.95); lo >= hi {
+		t.Errorf("reviews at 0.80 = %d, at 0.95 = %d; higher retention should cost more reviews", lo, hi)
+	}
+}
+
+func TestSimulateSkipsSuspendedLeeches(t *testing.T) {
+	reviews := func(leech flux.LeechPolicy) int {
+		s, err := flux.NewScheduler(flux.SchedulerConfig{DisableFuzzing: true, Leech: leech})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg := small()
+		cfg.Scheduler = s
+		var n int
+		for _, r := range mustSimulate(t, cfg).Reviews {
+			n += r
+		}
+		return n
+	}
+	tagged := reviews(flux.LeechPolicy{Threshold: 1})
+	suspended := reviews(flux.LeechPolicy{Threshold: 1, Action: flux.LeechSuspend})
+	if suspended >= tagged {
+		t.Errorf("reviews with suspended leeches = %d, with tagged leeches = %d; suspended cards should not be reviewed", suspended, tagged)
+	}
+}
+
+func TestSimulationDueSkipsSuspended(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sim := &simulation{cards: []flux.Card{
+		{CardID: 1, Due: start.Add(2 * time.Hour)},
+		{CardID: 2, Due: start, Queue: flux.QueueSuspended},
+		{CardID: 3, Due: start.Add(time.Hour)},
+		{CardID: 4, Due: start.Add(48 * time.Hour)},
+	}}
+	if got, want := sim.due(start.Add(24*time.Hour)), []int{2, 0}; !slices.Equal(got, want) {
+		t.Errorf("due = %v, want %v", got, want)
+	}
+}
+
+func TestSimulateCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Simulate(ctx, small()); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestSimulateInvalidConfig(t *testing.T) {
+	tests := map[string]func(*Config){
+		"deck size":    func(c *Config) { c.DeckSize = -1 },
+		"learn span":   func(c *Config) { c.LearnSpan = -1 },
+		"new cards":    func(c *Config) { c.NewCardsPerDay = -1 },
+		"reviews":      func(c *Config) { c.MaxReviewsPerDay = -1 },
+		"cost":         func(c *Config) { c.MaxCostPerDay = -time.Minute },
+		"first probs":  func(c *Config) { c.FirstRatingProbs = [4]float64{0.5, 0.5, 0.5, 0} },
+		"review probs": func(c *Config) { c.ReviewRatingProbs = [3]float64{1.5, -0.5, 0} },
+		"learn costs":  func(c *Config) { c.LearnCosts = [4]time.Duration{-time.Second} },
+		"review costs": func(c *Config) { c.ReviewCosts = [4]time.Duration{0, -time.Second} },
+	}
+	for name, mutate := range tests {
+		cfg := small()
+		mutate(&cfg)
+		if _, err := Simulate(context.Background(), cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: error = %v, want ErrInvalidConfig", name, err)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	probs := []float64{0.25, 0.5, 0.25}
+	for u, want := range map[float64]int{0: 0, 0.3: 1, 0.8: 2, 1: 2} {
+		if got := pick(probs, u); got != want {
+			t.Errorf("pick(%v) = %d, want %d", u, got, want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package flux
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// snapshotCfg is noFuzzCfg with snapshots recorded.
+func snapshotCfg() SchedulerConfig {
+	cfg := noFuzzCfg()
+	cfg.RecordSnapshots = true
+	return cfg
+}
+
+func TestReviewCardNoSnapshotByDefault(t *testing.T) {
+	s := mustScheduler(t, noFuzzCfg())
+	_, log := s.ReviewCard(reviewCard(t), Good, t0.Add(5*24*time.Hour))
+	if log.Snapshot != nil {
+		t.Errorf("Snapshot = %+v, want nil without RecordSnapshots", log.Snapshot)
+	}
+}
+
+func TestReviewCardSnapshot(t *testing.T) {
+	s := mustScheduler(t, snapshotCfg())
+	card := reviewCard(t)
+	now := t0.Add(5 * 24 * time.Hour)
+	c, log := s.ReviewCard(card, Good, now)
+
+	snap := log.Snapshot
+	if snap == nil {
+		t.Fatal("ReviewCard should fill in a snapshot")
+	}
+	assertFloat(t, "ElapsedDays", snap.ElapsedDays, 5)
+	assertFloat(t, "ScheduledDays", snap.ScheduledDays, c.Due.Sub(now).Hours()/24)
+	if snap.StateBefore != Review || snap.StateAfter != c.State {
+		t.Errorf("states = %v → %v, want Review → %v", snap.StateBefore, snap.StateAfter, c.State)
+	}
+	assertFloat(t, "StabilityBefore", *snap.StabilityBefore, 5)
+	assertFloat(t, "DifficultyBefore", *snap.DifficultyBefore, 5)
+	assertFloat(t, "RetrievabilityBefore", snap.RetrievabilityBefore, s.Retrievability(card, now))
+	assertFloat(t, "StabilityAfter", snap.StabilityAfter, *c.Stability)
+	assertFloat(t, "DifficultyAfter", snap.DifficultyAfter, *c.Difficulty)
+	assertFloat(t, "RetrievabilityAfter", snap.RetrievabilityAfter, s.Retrievability(c, c.Due))
+
+	// The snapshot does not alias the caller's card.
+	*card.Stability = 99
+	*card.Difficulty = 99
+	if *snap.StabilityBefore != 5 || *snap.DifficultyBefore != 5 {
+		t.Error("snapshot aliases the input card")
+	}
+}
+
+func TestReviewCardSnapshotNewCard(t *testing.T) {
+	s := mustScheduler(t, snapshotCfg())
+	c, log := s.ReviewCard(NewCard(1), Good, t0)
+	snap := log.Snapshot
+	if snap.StabilityBefore != nil || snap.DifficultyBefore != nil || snap.RetrievabilityBefore != 0 {
+		t.Errorf("new card snapshot before = %v, %v, %v, want nil, nil, 0",
+			snap.StabilityBefore, snap.DifficultyBefore, snap.RetrievabilityBefore)
+	}
+	if snap.StateBefore != Learning || snap.StabilityAfter != *c.Stability {
+		t.Errorf("snapshot = %+v", snap)
+	}
+}
+
+func TestReviewCardSnapshotStabilityWithoutLastReview(t *testing.T) {
+	// Stability without LastReview: no elapsed time, so no retrievability.
+	s := mustScheduler(t, snapshotCfg())
+	card := reviewCard(t)
+	card.LastReview = nil
+	_, log := s.ReviewCard(card, Good, t0)
+	if log.Snapshot.RetrievabilityBefore != 0 || *log.Snapshot.StabilityBefore != 5 {
+		t.Errorf("snapshot = %+v", log.Snapshot)
+	}
+}
+
+func TestReviewSnapshotJSON(t *testing.T) {
+	s := mustScheduler(t, snapshotCfg())
+	_, log := s.ReviewCard(reviewCard(t), Hard, t0.Add(3*24*time.Hour))
+	data, err := json.Marshal(log)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var got ReviewLog
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Snapshot == nil || got.Snapshot.StabilityAfter != log.Snapshot.StabilityAfter ||
+		got.Snapshot.StateBefore != Review || *got.Snapshot.StabilityBefore != 5 {
+		t.Errorf("round-trip snapshot = %+v, want %+v", got.Snapshot, log.Snapshot)
+	}
+
+	// Logs without a snapshot omit it.
+	data, err = json.Marshal(ReviewLog{CardID: 1, Rating: Good, ReviewDatetime: t0})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), "snapshot") {
+		t.Errorf("empty snapshot serialized: %s", data)
+	}
+}
+
+func TestSchedulerJSONRecordSnapshots(t *testing.T) {
+	s := mustScheduler(t, snapshotCfg())
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var s2 Scheduler
+	if err := json.Unmarshal(data, &s2); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !s2.recordSnapshots {
+		t.Error("recordSnapshots lost in JSON round trip")
+	}
+}
@@ -81,3 +81,80 @@ func (s *State) UnmarshalJSON(data []byte) error {
 	}
 	return s.UnmarshalText([]byte(str))
 }
+
+// Queue says whether a card is in normal rotation. It is orthogonal to
+// State: suspending or burying a card leaves its scheduling state intact.
+type Queue int
+
+const (
+	QueueActive    Queue = iota // In normal rotation.
+	QueueSuspended              // Never due until unsuspended.
+	QueueBuried                 // Not due before Card.BuriedUntil.
+)
+
+var (
+	queueNames  = [...]string{QueueActive: "Active", QueueSuspended: "Suspended", QueueBuried: "Buried"}
+	queueByName = map[string]Queue{
+		"Active":    QueueActive,
+		"Suspended": QueueSuspended,
+		"Buried":    QueueBuried,
+	}
+)
+
+// Compile-time interface checks.
+var (
+	_ fmt.Stringer             = Queue(0)
+	_ json.Marshaler           = Queue(0)
+	_ json.Unmarshaler         = (*Queue)(nil)
+	_ encoding.TextMarshaler   = Queue(0)
+	_ encoding.TextUnmarshaler = (*Queue)(nil)
+)
+
+func (q Queue) isValid() bool {
+	return q >= QueueActive && q <= QueueBuried
+}
+
+// String returns the name of the queue ("Active", "Suspended", "Buried").
+// For invalid values it returns "Queue(n)".
+func (q Queue) String() string {
+	if q.isValid() {
+		return queueNames[q]
+	}
+	return fmt.Sprintf("Queue(%d)", int(q))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (q Queue) MarshalText() ([]byte, error) {
+	if !q.isValid() {
+		return nil, fmt.Errorf("flux: invalid queue: %d", int(q))
+	}
+	return []byte(queueNames[q]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (q *Queue) UnmarshalText(text []byte) error {
+	v, ok := queueByName[string(text)]
+	if !ok {
+		return fmt.Errorf("flux: invalid queue: %q", text)
+	}
+	*q = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Queue serializes as a JSON string.
+func (q Queue) MarshalJSON() ([]byte, error) {
+	text, err := q.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Expects a JSON string.
+func (q *Queue) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("flux: invalid queue: %s", data)
+	}
+	return q.UnmarshalText([]byte(str))
+}